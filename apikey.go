@@ -0,0 +1,213 @@
+package pkgep
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base32"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/epicconsult/pkgep/jwt"
+	"github.com/epicconsult/pkgep/logger"
+	"github.com/gofiber/fiber/v2"
+	gojwt "github.com/golang-jwt/jwt/v5"
+)
+
+// DefaultAPIKeyPrefix starts every generated key, making leaked keys easy to
+// spot by secret scanners.
+const DefaultAPIKeyPrefix = "epk"
+
+// AuthTypeAPIKey is set in SubClaims.AuthType of requests authenticated with
+// an API key.
+const AuthTypeAPIKey = "api_key"
+
+var (
+	ErrInvalidAPIKey  = errors.New("api key is invalid")
+	ErrAPIKeyExpired  = errors.New("api key is expired")
+	ErrAPIKeyNotFound = errors.New("api key not found")
+)
+
+// APIKey is the stored record of a key. Only the SHA-256 hash of the secret
+// is kept, the full key is shown once when generated. Hash is left out of
+// JSON so listing keys does not expose it.
+type APIKey struct {
+	ID         string     `json:"id" gorm:"primaryKey;size:32"`
+	Hash       string     `json:"-" gorm:"size:64;not null"`
+	Name       string     `json:"name"`
+	Sub        SubClaims  `json:"sub" gorm:"serializer:json"`
+	ExpiresAt  *time.Time `json:"expires_at,omitempty"`
+	LastUsedAt *time.Time `json:"last_used_at,omitempty"`
+	CreatedAt  time.Time  `json:"created_at"`
+}
+
+func (k *APIKey) Expired(now time.Time) bool {
+	return k.ExpiresAt != nil && !now.Before(*k.ExpiresAt)
+}
+
+// APIKeyStore persists API keys, see NewGormAPIKeyStore and
+// NewRedisAPIKeyStore.
+type APIKeyStore interface {
+	Create(ctx context.Context, key *APIKey) error
+	// Get returns ErrAPIKeyNotFound for unknown ids.
+	Get(ctx context.Context, id string) (*APIKey, error)
+	Touch(ctx context.Context, id string, usedAt time.Time) error
+	Delete(ctx context.Context, id string) error
+}
+
+// How often LastUsedAt is written, so busy keys do not cost a write per
+// request.
+const apiKeyTouchInterval = time.Minute
+
+type APIKeys struct {
+	store  APIKeyStore
+	prefix string
+}
+
+// NewAPIKeys issues and authenticates keys of the form
+// "<prefix>_<id>_<secret>", prefix defaults to DefaultAPIKeyPrefix.
+func NewAPIKeys(store APIKeyStore, prefix string) *APIKeys {
+	if prefix == "" {
+		prefix = DefaultAPIKeyPrefix
+	}
+	return &APIKeys{store: store, prefix: prefix}
+}
+
+// Generate creates a key acting as sub, whose Scopes bound what it may do.
+// ttl 0 never expires. The returned key string cannot be recovered later.
+func (k *APIKeys) Generate(ctx context.Context, name string, sub SubClaims, ttl time.Duration) (string, *APIKey, error) {
+	id := make([]byte, 10)
+	secret := make([]byte, 32)
+	if _, err := rand.Read(id); err != nil {
+		return "", nil, err
+	}
+	if _, err := rand.Read(secret); err != nil {
+		return "", nil, err
+	}
+
+	sub.AuthType = AuthTypeAPIKey
+	key := &APIKey{
+		ID:        strings.ToLower(base32.StdEncoding.WithPadding(base32.NoPadding).EncodeToString(id)),
+		Name:      name,
+		Sub:       sub,
+		CreatedAt: time.Now(),
+	}
+	encodedSecret := base64.RawURLEncoding.EncodeToString(secret)
+	key.Hash = hashAPIKeySecret(encodedSecret)
+	if ttl > 0 {
+		expiresAt := key.CreatedAt.Add(ttl)
+		key.ExpiresAt = &expiresAt
+	}
+
+	if err := k.store.Create(ctx, key); err != nil {
+		return "", nil, err
+	}
+	return k.prefix + "_" + key.ID + "_" + encodedSecret, key, nil
+}
+
+// Authenticate looks up raw and checks its secret and expiry.
+func (k *APIKeys) Authenticate(ctx context.Context, raw string) (*APIKey, error) {
+	rest, ok := strings.CutPrefix(raw, k.prefix+"_")
+	if !ok {
+		return nil, ErrInvalidAPIKey
+	}
+	id, secret, ok := strings.Cut(rest, "_")
+	if !ok || id == "" || secret == "" {
+		return nil, ErrInvalidAPIKey
+	}
+
+	key, err := k.store.Get(ctx, id)
+	if errors.Is(err, ErrAPIKeyNotFound) {
+		return nil, ErrInvalidAPIKey
+	}
+	if err != nil {
+		return nil, err
+	}
+	if subtle.ConstantTimeCompare([]byte(key.Hash), []byte(hashAPIKeySecret(secret))) != 1 {
+		return nil, ErrInvalidAPIKey
+	}
+
+	now := time.Now()
+	if key.Expired(now) {
+		return nil, ErrAPIKeyExpired
+	}
+	if key.LastUsedAt == nil || now.Sub(*key.LastUsedAt) >= apiKeyTouchInterval {
+		// The key is valid, a failed bookkeeping write must not reject it.
+		if err := k.store.Touch(ctx, key.ID, now); err != nil {
+			reportTouchError(ctx, key.ID, err)
+		} else {
+			key.LastUsedAt = &now
+		}
+	}
+	return key, nil
+}
+
+func reportTouchError(ctx context.Context, id string, err error) {
+	if logger.Logger == nil {
+		return
+	}
+	logger.Logger.Error(logger.With(ctx, "api_key_id", id), "failed to record api key use: "+err.Error())
+}
+
+// Revoke deletes the key with id.
+func (k *APIKeys) Revoke(ctx context.Context, id string) error {
+	return k.store.Delete(ctx, id)
+}
+
+func hashAPIKeySecret(secret string) string {
+	sum := sha256.Sum256([]byte(secret))
+	return hex.EncodeToString(sum[:])
+}
+
+type APIKeyConfig struct {
+	// Header carrying the key. Default "X-API-Key".
+	Header string
+
+	// ContextKey is the c.Locals key a *VerifiedToken holding the key's
+	// SubClaims and ID as "jti" is stored under.
+	// Default jwt.DefaultContextKey, shared with JWT auth so RequireRoles and
+	// RequireScopes work with either.
+	ContextKey string
+}
+
+// Middleware authenticates the request API key and stores it as a
+// *VerifiedToken the way JWT auth does, so handlers do not depend on the auth
+// method.
+//
+//	app.Use("/partner", apiKeys.Middleware(), pkgep.RequireScopes("orders:read"))
+func (k *APIKeys) Middleware(config ...APIKeyConfig) fiber.Handler {
+	var cfg APIKeyConfig
+	if len(config) > 0 {
+		cfg = config[0]
+	}
+	if cfg.Header == "" {
+		cfg.Header = "X-API-Key"
+	}
+	if cfg.ContextKey == "" {
+		cfg.ContextKey = jwt.DefaultContextKey
+	}
+
+	return func(c *fiber.Ctx) error {
+		raw := strings.TrimSpace(c.Get(cfg.Header))
+		if raw == "" {
+			return ErrorResponse(c, Unauthorized)
+		}
+
+		key, err := k.Authenticate(c.UserContext(), raw)
+		switch {
+		case errors.Is(err, ErrInvalidAPIKey), errors.Is(err, ErrAPIKeyExpired):
+			return ErrorResponse(c, Unauthorized, err.Error())
+		case err != nil:
+			return ErrorResponse(c, Internal)
+		}
+
+		token := &VerifiedToken{Sub: key.Sub, Jti: key.ID, RegisteredClaims: gojwt.RegisteredClaims{ID: key.ID}}
+		c.Locals(cfg.ContextKey, token)
+		c.SetUserContext(logger.With(c.UserContext(), token))
+		return c.Next()
+	}
+}
@@ -0,0 +1,124 @@
+package pkgep
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strconv"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"gorm.io/gorm"
+)
+
+type gormAPIKeyStore struct {
+	db *gorm.DB
+}
+
+// NewGormAPIKeyStore keeps API keys in the api_keys table, create it with
+// db.AutoMigrate(&pkgep.APIKey{}).
+func NewGormAPIKeyStore(db *gorm.DB) APIKeyStore {
+	return &gormAPIKeyStore{db: db}
+}
+
+func (s *gormAPIKeyStore) Create(ctx context.Context, key *APIKey) error {
+	return s.db.WithContext(ctx).Create(key).Error
+}
+
+func (s *gormAPIKeyStore) Get(ctx context.Context, id string) (*APIKey, error) {
+	var key APIKey
+	err := s.db.WithContext(ctx).Where("id = ?", id).Take(&key).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrAPIKeyNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &key, nil
+}
+
+func (s *gormAPIKeyStore) Touch(ctx context.Context, id string, usedAt time.Time) error {
+	return s.db.WithContext(ctx).Model(&APIKey{}).Where("id = ?", id).Update("last_used_at", usedAt).Error
+}
+
+func (s *gormAPIKeyStore) Delete(ctx context.Context, id string) error {
+	return s.db.WithContext(ctx).Where("id = ?", id).Delete(&APIKey{}).Error
+}
+
+type redisAPIKeyStore struct {
+	client redis.UniversalClient
+	prefix string
+}
+
+// legacyAPIKeyHash reads the hash of keys stored with it in the JSON record.
+type legacyAPIKeyHash struct {
+	Hash string `json:"hash"`
+}
+
+// NewRedisAPIKeyStore keeps each API key in a hash under prefix+id, expiring
+// together with the key. The secret hash is a field of its own, APIKey.Hash
+// is not marshalled.
+func NewRedisAPIKeyStore(client redis.UniversalClient, prefix string) APIKeyStore {
+	return &redisAPIKeyStore{client: client, prefix: prefix}
+}
+
+func (s *redisAPIKeyStore) Create(ctx context.Context, key *APIKey) error {
+	data, err := json.Marshal(key)
+	if err != nil {
+		return err
+	}
+
+	_, err = s.client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.HSet(ctx, s.prefix+key.ID, "key", data, "hash", key.Hash)
+		if key.ExpiresAt != nil {
+			pipe.ExpireAt(ctx, s.prefix+key.ID, *key.ExpiresAt)
+		}
+		return nil
+	})
+	return err
+}
+
+func (s *redisAPIKeyStore) Get(ctx context.Context, id string) (*APIKey, error) {
+	fields, err := s.client.HGetAll(ctx, s.prefix+id).Result()
+	if err != nil {
+		return nil, err
+	}
+	data, ok := fields["key"]
+	if !ok {
+		return nil, ErrAPIKeyNotFound
+	}
+
+	var key APIKey
+	if err := json.Unmarshal([]byte(data), &key); err != nil {
+		return nil, err
+	}
+	if key.Hash = fields["hash"]; key.Hash == "" {
+		var legacy legacyAPIKeyHash
+		if err := json.Unmarshal([]byte(data), &legacy); err != nil {
+			return nil, err
+		}
+		key.Hash = legacy.Hash
+	}
+	if usedAt, err := strconv.ParseInt(fields["last_used"], 10, 64); err == nil {
+		at := time.UnixMilli(usedAt)
+		key.LastUsedAt = &at
+	}
+	return &key, nil
+}
+
+// touchAPIKey records the last use in its own field so the stored key is
+// never rewritten, and does not resurrect a deleted key.
+var touchAPIKey = redis.NewScript(`
+if redis.call("HEXISTS", KEYS[1], "key") == 1 then
+	return redis.call("HSET", KEYS[1], "last_used", ARGV[1])
+end
+return 0
+`)
+
+func (s *redisAPIKeyStore) Touch(ctx context.Context, id string, usedAt time.Time) error {
+	return touchAPIKey.Run(ctx, s.client, []string{s.prefix + id}, usedAt.UnixMilli()).Err()
+}
+
+func (s *redisAPIKeyStore) Delete(ctx context.Context, id string) error {
+	return s.client.Del(ctx, s.prefix+id).Err()
+}
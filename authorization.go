@@ -0,0 +1,148 @@
+package pkgep
+
+import (
+	"slices"
+
+	"github.com/epicconsult/pkgep/jwt"
+	"github.com/gofiber/fiber/v2"
+	"github.com/spf13/viper"
+)
+
+// RoleHierarchy maps a role to the roles it includes, e.g.
+// {"admin": {"staff"}, "staff": {"user"}} makes admin ⊇ staff ⊇ user.
+type RoleHierarchy map[string][]string
+
+// Policy decides whether the authenticated caller may proceed.
+type Policy func(c *fiber.Ctx, sub SubClaims) bool
+
+type AuthzConfig struct {
+	Hierarchy RoleHierarchy
+
+	// ContextKey is the c.Locals key holding verified claims.
+	// Default jwt.DefaultContextKey.
+	ContextKey string
+}
+
+// Authorizer enforces roles, scopes and policies on SubClaims. Its handlers
+// must run after JWT verification.
+type Authorizer struct {
+	grants     map[string][]string // role -> every role it includes, itself too
+	contextKey string
+}
+
+func NewAuthorizer(cfg AuthzConfig) *Authorizer {
+	if cfg.ContextKey == "" {
+		cfg.ContextKey = jwt.DefaultContextKey
+	}
+
+	grants := map[string][]string{}
+	for role := range cfg.Hierarchy {
+		grants[role] = expandRole(cfg.Hierarchy, role, map[string]bool{})
+	}
+
+	return &Authorizer{grants: grants, contextKey: cfg.ContextKey}
+}
+
+// RoleHierarchyFromViper reads a hierarchy declared in configuration, e.g.
+//
+//	{"auth": {"roles": {"admin": ["staff"], "staff": ["user"]}}}
+func RoleHierarchyFromViper(key string) RoleHierarchy {
+	return RoleHierarchy(viper.GetStringMapStringSlice(key))
+}
+
+func expandRole(hierarchy RoleHierarchy, role string, seen map[string]bool) []string {
+	if seen[role] {
+		return nil
+	}
+	seen[role] = true
+
+	roles := []string{role}
+	for _, included := range hierarchy[role] {
+		roles = append(roles, expandRole(hierarchy, included, seen)...)
+	}
+	return roles
+}
+
+// HasRole reports whether role grants required through the hierarchy.
+func (a *Authorizer) HasRole(role string, required string) bool {
+	if role == required {
+		return true
+	}
+	return slices.Contains(a.grants[role], required)
+}
+
+// RequireRoles lets the request through when the caller holds any of roles.
+func (a *Authorizer) RequireRoles(roles ...string) fiber.Handler {
+	return a.RequirePolicy(func(c *fiber.Ctx, sub SubClaims) bool {
+		return slices.ContainsFunc(roles, func(required string) bool {
+			return a.HasRole(sub.Role, required)
+		})
+	})
+}
+
+// RequireScopes lets the request through when the caller holds all of scopes.
+func (a *Authorizer) RequireScopes(scopes ...string) fiber.Handler {
+	return a.RequirePolicy(func(c *fiber.Ctx, sub SubClaims) bool {
+		for _, scope := range scopes {
+			if !slices.Contains(sub.Scopes, scope) {
+				return false
+			}
+		}
+		return true
+	})
+}
+
+// RequirePolicy lets the request through when policy returns true.
+func (a *Authorizer) RequirePolicy(policy Policy) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		sub, ok := a.subClaims(c)
+		if !ok {
+			return ErrorResponse(c, Unauthorized)
+		}
+		if !policy(c, sub) {
+			return ErrorResponse(c, Forbidden)
+		}
+		return c.Next()
+	}
+}
+
+func (a *Authorizer) subClaims(c *fiber.Ctx) (SubClaims, bool) {
+	switch claims := c.Locals(a.contextKey).(type) {
+	case *VerifiedToken:
+		if claims == nil {
+			return SubClaims{}, false
+		}
+		return claims.subClaims(), true
+	case VerifiedToken:
+		return claims.subClaims(), true
+	case *SubClaims:
+		if claims == nil {
+			return SubClaims{}, false
+		}
+		return *claims, true
+	case SubClaims:
+		return claims, true
+	default:
+		return SubClaims{}, false
+	}
+}
+
+var authorizer = NewAuthorizer(AuthzConfig{})
+
+// InitAuthorizer configures the authorizer behind RequireRoles,
+// RequireScopes and RequirePolicy, call it before registering routes.
+func InitAuthorizer(cfg AuthzConfig) {
+	authorizer = NewAuthorizer(cfg)
+}
+
+func RequireRoles(roles ...string) fiber.Handler {
+	return authorizer.RequireRoles(roles...)
+}
+
+func RequireScopes(scopes ...string) fiber.Handler {
+	return authorizer.RequireScopes(scopes...)
+}
+
+func RequirePolicy(policy Policy) fiber.Handler {
+	return authorizer.RequirePolicy(policy)
+}
@@ -0,0 +1,149 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/epicconsult/pkgep/jwt"
+	gojwt "github.com/golang-jwt/jwt/v5"
+)
+
+// sentinels names the jwt package errors a token can be rejected with.
+var sentinels = []struct {
+	name string
+	err  error
+}{
+	{"ErrTokenExpired", jwt.ErrTokenExpired},
+	{"ErrTokenNotValidYet", jwt.ErrTokenNotValidYet},
+	{"ErrTokenUsedBeforeIssued", jwt.ErrTokenUsedBeforeIssued},
+	{"ErrTokenMalformed", jwt.ErrTokenMalformed},
+	{"ErrTokenSignatureInvalid", jwt.ErrTokenSignatureInvalid},
+	{"ErrUnexpectedSigningMethod", jwt.ErrUnexpectedSigningMethod},
+	{"ErrUnknownKeyID", jwt.ErrUnknownKeyID},
+	{"ErrTokenInvalidIssuer", jwt.ErrTokenInvalidIssuer},
+	{"ErrTokenInvalidAudience", jwt.ErrTokenInvalidAudience},
+	{"ErrTokenRequiredClaimMissing", jwt.ErrTokenRequiredClaimMissing},
+	{"ErrTokenRevoked", jwt.ErrTokenRevoked},
+	{"ErrTokenBindingMismatch", jwt.ErrTokenBindingMismatch},
+	{"ErrInvalidToken", jwt.ErrInvalidToken},
+}
+
+func verify(args []string) error {
+	fs := flag.NewFlagSet("verify", flag.ExitOnError)
+	keys := addKeyFlags(fs)
+	fs.Parse(args)
+
+	token, err := tokenArg(fs)
+	if err != nil {
+		return err
+	}
+
+	j, err := keys.epicJWT(false)
+	if err != nil {
+		return err
+	}
+
+	if err := j.Verify(token); err != nil {
+		return fmt.Errorf("%s: %w", reason(err), err)
+	}
+	fmt.Println("token is valid")
+	return nil
+}
+
+func inspect(args []string) error {
+	fs := flag.NewFlagSet("inspect", flag.ExitOnError)
+	keys := addKeyFlags(fs)
+	fs.Parse(args)
+
+	token, err := tokenArg(fs)
+	if err != nil {
+		return err
+	}
+
+	claims := gojwt.MapClaims{}
+	parsed, _, err := gojwt.NewParser().ParseUnverified(token, claims)
+	if err != nil {
+		return fmt.Errorf("%s: %w", reason(jwt.ErrTokenMalformed), err)
+	}
+
+	printJSON("header", parsed.Header)
+	printJSON("claims", claims)
+	printTimes(claims)
+
+	if *keys.key == "" && *keys.secret == "" {
+		fmt.Println("\nsignature: not verified, pass -key or -secret")
+		return nil
+	}
+
+	j, err := keys.epicJWT(false)
+	if err != nil {
+		return err
+	}
+	if err := j.Verify(token); err != nil {
+		fmt.Printf("\nstatus: rejected\nreason: %s\nerror:  %v\n", reason(err), err)
+		var verr *jwt.VerificationError
+		if errors.As(err, &verr) && verr.Claim != "" {
+			fmt.Printf("claim:  %s\n", verr.Claim)
+		}
+		os.Exit(1)
+	}
+	fmt.Println("\nstatus: valid")
+	return nil
+}
+
+// tokenArg reads the token from the first argument, or stdin when absent or "-".
+func tokenArg(fs *flag.FlagSet) (string, error) {
+	token := fs.Arg(0)
+	if token == "" || token == "-" {
+		data, err := io.ReadAll(os.Stdin)
+		if err != nil {
+			return "", err
+		}
+		token = string(data)
+	}
+
+	token = strings.TrimSpace(strings.TrimPrefix(strings.TrimSpace(token), "Bearer "))
+	if token == "" {
+		return "", errors.New("requires a token argument")
+	}
+	return token, nil
+}
+
+func reason(err error) string {
+	for _, s := range sentinels {
+		if errors.Is(err, s.err) {
+			return s.name
+		}
+	}
+	return "unknown"
+}
+
+func printJSON(title string, v any) {
+	data, _ := json.MarshalIndent(v, "", "  ")
+	fmt.Printf("%s:\n%s\n", title, data)
+}
+
+func printTimes(claims gojwt.MapClaims) {
+	for _, claim := range []string{"iat", "nbf", "exp"} {
+		value, ok := claims[claim].(float64)
+		if !ok {
+			continue
+		}
+		at := time.Unix(int64(value), 0)
+		fmt.Printf("%s: %s (%s)\n", claim, at.Format(time.RFC3339), relative(at))
+	}
+}
+
+func relative(at time.Time) string {
+	d := time.Until(at).Round(time.Second)
+	if d < 0 {
+		return fmt.Sprintf("%s ago", -d)
+	}
+	return fmt.Sprintf("in %s", d)
+}
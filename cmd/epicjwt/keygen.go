@@ -0,0 +1,103 @@
+package main
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"flag"
+	"fmt"
+	"os"
+)
+
+// keygen writes <out>.pem and <out>.pub in the formats read by
+// jwt.LoadPrivateKey, jwt.LoadECPrivateKey, jwt.LoadEdPrivateKey and their
+// public counterparts. HMAC secrets are written to <out>.secret.
+func keygen(args []string) error {
+	fs := flag.NewFlagSet("keygen", flag.ExitOnError)
+	keyType := fs.String("type", "rsa", "key type: rsa, ec, ed25519 or hmac")
+	bits := fs.Int("bits", 2048, "rsa modulus size")
+	curve := fs.String("curve", "P-256", "ec curve: P-256, P-384 or P-521")
+	size := fs.Int("size", 64, "hmac secret size in bytes")
+	out := fs.String("out", "jwt", "output file name without extension")
+	fs.Parse(args)
+
+	var (
+		private *pem.Block
+		public  crypto.PublicKey
+	)
+
+	switch *keyType {
+	case "rsa":
+		key, err := rsa.GenerateKey(rand.Reader, *bits)
+		if err != nil {
+			return err
+		}
+		private = &pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(key)}
+		public = &key.PublicKey
+	case "ec":
+		var c elliptic.Curve
+		switch *curve {
+		case "P-256":
+			c = elliptic.P256()
+		case "P-384":
+			c = elliptic.P384()
+		case "P-521":
+			c = elliptic.P521()
+		default:
+			return fmt.Errorf("unsupported curve %q", *curve)
+		}
+		key, err := ecdsa.GenerateKey(c, rand.Reader)
+		if err != nil {
+			return err
+		}
+		der, err := x509.MarshalECPrivateKey(key)
+		if err != nil {
+			return err
+		}
+		private = &pem.Block{Type: "EC PRIVATE KEY", Bytes: der}
+		public = &key.PublicKey
+	case "ed25519":
+		pub, key, err := ed25519.GenerateKey(rand.Reader)
+		if err != nil {
+			return err
+		}
+		der, err := x509.MarshalPKCS8PrivateKey(key)
+		if err != nil {
+			return err
+		}
+		private = &pem.Block{Type: "PRIVATE KEY", Bytes: der}
+		public = pub
+	case "hmac":
+		secret := make([]byte, *size)
+		if _, err := rand.Read(secret); err != nil {
+			return err
+		}
+		return writeFile(*out+".secret", []byte(base64.RawURLEncoding.EncodeToString(secret)+"\n"), 0600)
+	default:
+		return fmt.Errorf("unsupported key type %q", *keyType)
+	}
+
+	der, err := x509.MarshalPKIXPublicKey(public)
+	if err != nil {
+		return err
+	}
+
+	if err := writeFile(*out+".pem", pem.EncodeToMemory(private), 0600); err != nil {
+		return err
+	}
+	return writeFile(*out+".pub", pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der}), 0644)
+}
+
+func writeFile(path string, data []byte, perm os.FileMode) error {
+	if err := os.WriteFile(path, data, perm); err != nil {
+		return err
+	}
+	fmt.Println("wrote", path)
+	return nil
+}
@@ -0,0 +1,133 @@
+package main
+
+import (
+	"crypto/ed25519"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/epicconsult/pkgep/jwt"
+	gojwt "github.com/golang-jwt/jwt/v5"
+)
+
+// keyFlags are shared by sign, verify and inspect.
+type keyFlags struct {
+	alg    *string
+	key    *string
+	secret *string
+	kid    *string
+	iss    *string
+	aud    *string
+	leeway *time.Duration
+	signer *string
+	weak   *bool
+}
+
+func addKeyFlags(fs *flag.FlagSet) keyFlags {
+	return keyFlags{
+		alg:    fs.String("alg", "RS256", "signing algorithm, e.g. HS256, RS256, PS256, ES256, EdDSA"),
+		key:    fs.String("key", "", "PEM key file: private key to sign, public key to verify"),
+		secret: fs.String("secret", os.Getenv("JWT_SECRET_KEY"), "hmac secret, defaults to $JWT_SECRET_KEY"),
+		kid:    fs.String("kid", "", "key id stamped in the token header"),
+		iss:    fs.String("iss", "", "issuer"),
+		aud:    fs.String("aud", "", "audience, comma separated"),
+		leeway: fs.Duration("leeway", 0, "tolerated clock skew when verifying"),
+		signer: fs.String("signer", "", "unix socket of an epicjwt signerd to sign with instead of -key"),
+		weak:   fs.Bool("weak-key", false, "accept a legacy hmac secret or rsa key below the minimum strength"),
+	}
+}
+
+func (f keyFlags) audience() []string {
+	if *f.aud == "" {
+		return nil
+	}
+	return strings.Split(*f.aud, ",")
+}
+
+// epicJWT builds the EpicJWT described by the flags. private selects whether
+// -key holds a private (sign) or public (verify) key.
+func (f keyFlags) epicJWT(private bool) (jwt.EpicJWT, error) {
+	j, err := f.newJWT(private)
+	if errors.Is(err, jwt.ErrWeakKey) && !*f.weak {
+		return nil, fmt.Errorf("%w, pass -weak-key to use it anyway", err)
+	}
+	return j, err
+}
+
+func (f keyFlags) newJWT(private bool) (jwt.EpicJWT, error) {
+	method := gojwt.GetSigningMethod(*f.alg)
+	if method == nil {
+		return nil, fmt.Errorf("unsupported algorithm %q", *f.alg)
+	}
+
+	cfg := jwt.Config{
+		Algorithm: method,
+		Issuer:    *f.iss,
+		Audience:  f.audience(),
+		Leeway:    *f.leeway,
+		WeakKey:   *f.weak,
+	}
+
+	// The keyring stamps KeyID in the "kid" header of signed tokens.
+	if private && *f.kid != "" {
+		cfg.KeyID = *f.kid
+		cfg.Keys = jwt.NewStaticKeys(&jwt.JWKS{})
+	}
+
+	if _, ok := method.(*gojwt.SigningMethodHMAC); ok {
+		if *f.secret == "" {
+			return nil, errors.New("requires -secret")
+		}
+		cfg.Secret = *f.secret
+		return jwt.New(cfg)
+	}
+
+	if private && *f.signer != "" {
+		signer, err := jwt.NewSocketSigner(*f.signer)
+		if err != nil {
+			return nil, err
+		}
+		cfg.Signer = signer
+		return jwt.New(cfg)
+	}
+
+	if *f.key == "" {
+		return nil, errors.New("requires -key")
+	}
+
+	var err error
+	switch method.(type) {
+	case *gojwt.SigningMethodRSA, *gojwt.SigningMethodRSAPSS:
+		if private {
+			if cfg.PrivateKey, err = jwt.LoadPrivateKey(*f.key); err == nil {
+				cfg.PublicKey = &cfg.PrivateKey.PublicKey
+			}
+		} else {
+			cfg.PublicKey, err = jwt.LoadPublicKey(*f.key)
+		}
+	case *gojwt.SigningMethodECDSA:
+		if private {
+			if cfg.ECPrivateKey, err = jwt.LoadECPrivateKey(*f.key); err == nil {
+				cfg.ECPublicKey = &cfg.ECPrivateKey.PublicKey
+			}
+		} else {
+			cfg.ECPublicKey, err = jwt.LoadECPublicKey(*f.key)
+		}
+	case *gojwt.SigningMethodEd25519:
+		if private {
+			if cfg.EdPrivateKey, err = jwt.LoadEdPrivateKey(*f.key); err == nil {
+				cfg.EdPublicKey = cfg.EdPrivateKey.Public().(ed25519.PublicKey)
+			}
+		} else {
+			cfg.EdPublicKey, err = jwt.LoadEdPublicKey(*f.key)
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return jwt.New(cfg)
+}
@@ -0,0 +1,58 @@
+// Command epicjwt generates keys, signs, verifies and inspects tokens with
+// the pkgep jwt package, e.g. to call protected endpoints while developing.
+//
+//	epicjwt keygen  -type rsa -out certs/jwt
+//	epicjwt sign    -alg RS256 -key certs/jwt.pem -sub 42 -exp 1h
+//	epicjwt verify  -alg RS256 -key certs/jwt.pub <token>
+//	epicjwt inspect -alg RS256 -key certs/jwt.pub <token>
+//	epicjwt signerd -key certs/jwt.pem -socket /tmp/signer.sock
+package main
+
+import (
+	"fmt"
+	"os"
+)
+
+const usage = `usage: epicjwt <command> [flags]
+
+commands:
+  keygen   generate a signing key (rsa, ec, ed25519 or hmac)
+  sign     sign claims into a token
+  verify   verify a token signature and claims
+  inspect  print token header and claims, and why it is rejected
+  signerd  serve a private key for "sign -signer" over a unix socket
+
+run "epicjwt <command> -h" for command flags.
+`
+
+func main() {
+	if len(os.Args) < 2 {
+		fmt.Fprint(os.Stderr, usage)
+		os.Exit(2)
+	}
+
+	var err error
+	switch os.Args[1] {
+	case "keygen":
+		err = keygen(os.Args[2:])
+	case "sign":
+		err = sign(os.Args[2:])
+	case "verify":
+		err = verify(os.Args[2:])
+	case "inspect":
+		err = inspect(os.Args[2:])
+	case "signerd":
+		err = signerd(os.Args[2:])
+	case "-h", "--help", "help":
+		fmt.Print(usage)
+		return
+	default:
+		fmt.Fprintf(os.Stderr, "unknown command %q\n\n%s", os.Args[1], usage)
+		os.Exit(2)
+	}
+
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "error:", err)
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,67 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	gojwt "github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+)
+
+func sign(args []string) error {
+	fs := flag.NewFlagSet("sign", flag.ExitOnError)
+	keys := addKeyFlags(fs)
+	claimsJSON := fs.String("claims", "", "claims as JSON, or @file to read them from a file")
+	sub := fs.String("sub", "", "subject")
+	exp := fs.Duration("exp", time.Hour, "token lifetime, 0 for no expiry")
+	fs.Parse(args)
+
+	claims := gojwt.MapClaims{}
+	if *claimsJSON != "" {
+		data := []byte(*claimsJSON)
+		if path, ok := strings.CutPrefix(*claimsJSON, "@"); ok {
+			var err error
+			if data, err = os.ReadFile(path); err != nil {
+				return err
+			}
+		}
+		if err := json.Unmarshal(data, &claims); err != nil {
+			return fmt.Errorf("invalid -claims: %w", err)
+		}
+	}
+
+	now := time.Now()
+	claims["iat"] = now.Unix()
+	if _, ok := claims["jti"]; !ok {
+		claims["jti"] = uuid.NewString()
+	}
+	if *exp > 0 {
+		claims["exp"] = now.Add(*exp).Unix()
+	}
+	if *sub != "" {
+		claims["sub"] = *sub
+	}
+	if *keys.iss != "" {
+		claims["iss"] = *keys.iss
+	}
+	if aud := keys.audience(); len(aud) > 0 {
+		claims["aud"] = aud
+	}
+
+	j, err := keys.epicJWT(true)
+	if err != nil {
+		return err
+	}
+
+	token, err := j.Sign(claims)
+	if err != nil {
+		return err
+	}
+
+	fmt.Println(token)
+	return nil
+}
@@ -0,0 +1,53 @@
+package main
+
+import (
+	"crypto"
+	"errors"
+	"flag"
+	"fmt"
+	"net"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/epicconsult/pkgep/jwt"
+)
+
+// signerd holds a private key and signs for other processes over a Unix
+// socket, a stand-in for an HSM or KMS while developing.
+func signerd(args []string) error {
+	fs := flag.NewFlagSet("signerd", flag.ExitOnError)
+	key := fs.String("key", "", "PEM private key file (rsa or ec)")
+	password := fs.String("password", os.Getenv("JWT_KEY_PASSWORD"), "private key password, defaults to $JWT_KEY_PASSWORD")
+	socket := fs.String("socket", "epicjwt-signer.sock", "unix socket path")
+	fs.Parse(args)
+
+	if *key == "" {
+		return errors.New("requires -key")
+	}
+	private, err := jwt.LoadPrivateKeyFrom(jwt.FileKey(*key), []byte(*password))
+	if err != nil {
+		return err
+	}
+	signer, ok := private.(crypto.Signer)
+	if !ok {
+		return fmt.Errorf("unsupported private key type %T", private)
+	}
+
+	os.Remove(*socket)
+	l, err := net.Listen("unix", *socket)
+	if err != nil {
+		return err
+	}
+	defer os.Remove(*socket)
+
+	stop := make(chan os.Signal, 1)
+	signal.Notify(stop, os.Interrupt, syscall.SIGTERM)
+	go func() {
+		<-stop
+		l.Close()
+	}()
+
+	fmt.Printf("signing on %s\n", *socket)
+	return jwt.ServeSigner(l, signer)
+}
@@ -32,6 +32,7 @@ const (
 	InvalidToken                    AppResponseStatus = 40006
 	LockedAccount                   AppResponseStatus = 40007
 	Unauthorized                    AppResponseStatus = 40100
+	TokenExpired                    AppResponseStatus = 40101
 	Forbidden                       AppResponseStatus = 40300
 	NotFound                        AppResponseStatus = 40400
 	MethodNotAllowed                AppResponseStatus = 40500
@@ -57,6 +58,7 @@ var StatusCodeMap = map[AppResponseStatus]string{
 	Internal:       "Internal Server Error",
 	InvalidToken:   "The token is incorrect",
 	Unauthorized:   "Unauthorized",
+	TokenExpired:   "The token has expired",
 	NotFound:       "Not Found",
 	EntityTooLarge: "Request body size limit exceeded (max 30MB)",
 	Conflict:       "Conflict",
@@ -132,6 +134,15 @@ func ErrorResponse(c *fiber.Ctx, appStatus AppResponseStatus, messages ...string
 			Message:    message,
 		}
 		httpStatus = http.StatusUnauthorized
+	case TokenExpired:
+		if message == "" {
+			message = StatusCodeMap[TokenExpired]
+		}
+		resError = ApiResponse{
+			StatusCode: TokenExpired,
+			Message:    message,
+		}
+		httpStatus = http.StatusUnauthorized
 	case Forbidden:
 		if message == "" {
 			message = StatusCodeMap[Forbidden]
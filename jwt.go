@@ -2,15 +2,18 @@ package pkgep
 
 import (
 	"crypto/rsa"
-	"errors"
+	"encoding/json"
 	"fmt"
 	"log"
 	"os"
+	"strconv"
 	"strings"
+	"sync"
 	"time"
 
+	epicjwt "github.com/epicconsult/pkgep/jwt"
 	"github.com/gofiber/fiber/v2"
-	"github.com/golang-jwt/jwt/v4"
+	"github.com/golang-jwt/jwt/v5"
 	"github.com/sirupsen/logrus"
 )
 
@@ -26,27 +29,137 @@ type AccessToken struct {
 }
 
 type SubClaims struct {
-	UserID      int    `json:"user_id"`
-	ParID       int    `json:"par_id"`
-	Email       string `json:"email"`
-	DisplayName string `json:"display_name"`
-	Role        string `json:"role"`
-	DeviceID    string `json:"device_id"`
-	AuthType    string `json:"auth_type"`
+	UserID      int      `json:"user_id"`
+	ParID       int      `json:"par_id"`
+	Email       string   `json:"email"`
+	DisplayName string   `json:"display_name"`
+	Role        string   `json:"role"`
+	DeviceID    string   `json:"device_id"`
+	AuthType    string   `json:"auth_type"`
+	Scopes      []string `json:"scopes,omitempty"`
+
+	// Actor is who is acting on behalf of the user, nil unless the token was
+	// obtained through token exchange. It is read from the "act" claim.
+	Actor *epicjwt.Actor `json:"-"`
+}
+
+// Impersonated reports whether someone else acts on behalf of the user.
+func (s SubClaims) Impersonated() bool {
+	return s.Actor != nil
 }
 
 // MyCustomClaims defines the structure of the entire JWT payload
 type VerifiedToken struct {
-	Sub  SubClaims `json:"sub"`
-	Jti  string    `json:"jti"`
-	Type string    `json:"type"`
+	Sub  SubClaims      `json:"sub"`
+	Jti  string         `json:"jti"`
+	Type string         `json:"type"`
+	Act  *epicjwt.Actor `json:"act,omitempty"`
 	jwt.RegisteredClaims
 }
 
+// subClaims returns Sub with its Actor filled from "act".
+func (v *VerifiedToken) subClaims() SubClaims {
+	sub := v.Sub
+	if sub.Actor == nil {
+		sub.Actor = v.Act
+	}
+	return sub
+}
+
+// GetSubject identifies the user by Sub.UserID, "sub" holds SubClaims rather
+// than a string. It lets required subject checks and subject revocation work.
+func (v VerifiedToken) GetSubject() (string, error) {
+	if v.Sub.UserID == 0 {
+		return "", nil
+	}
+	return strconv.Itoa(v.Sub.UserID), nil
+}
+
+// Legacy key locations, used when InitJWT has not been called.
+const (
+	legacyPrivateKeyPath = "certs/private.key"
+	legacyPublicKeyPath  = "certs/public.key"
+)
+
+var (
+	tokenJWT  epicjwt.EpicJWT
+	tokenJWTs sync.Map // secret env name -> *lazyJWT
+)
+
+type lazyJWT struct {
+	mu  sync.Mutex
+	jwt epicjwt.EpicJWT
+}
+
+// InitJWT sets the EpicJWT behind Sign, VerifyTokenHeader and JWTProtected,
+// call it before serving requests.
+func InitJWT(j epicjwt.EpicJWT) {
+	tokenJWT = j
+}
+
+// defaultJWT loads the keys once: RS256 with the legacy certs/ key pair when
+// certs/public.key exists, otherwise HS256 with the secret in the
+// SecretPublicKeyEnvName environment variable. Failed loads are retried on
+// the next call.
+func defaultJWT(SecretPublicKeyEnvName string) (epicjwt.EpicJWT, error) {
+	if tokenJWT != nil {
+		return tokenJWT, nil
+	}
+
+	value, _ := tokenJWTs.LoadOrStore(SecretPublicKeyEnvName, &lazyJWT{})
+	lazy := value.(*lazyJWT)
+	lazy.mu.Lock()
+	defer lazy.mu.Unlock()
+
+	if lazy.jwt == nil {
+		j, err := loadJWT(SecretPublicKeyEnvName)
+		if err != nil {
+			return nil, err
+		}
+		lazy.jwt = j
+	}
+	return lazy.jwt, nil
+}
+
+// loadJWT accepts legacy keys below the strength required by epicjwt.New, so
+// existing deployments keep working, and warns about them.
+func loadJWT(SecretPublicKeyEnvName string) (epicjwt.EpicJWT, error) {
+	if _, err := os.Stat(legacyPublicKeyPath); err != nil {
+		secret := GodotEnv(SecretPublicKeyEnvName)
+		if secret == "" {
+			return nil, fmt.Errorf("environment variable %s is not set", SecretPublicKeyEnvName)
+		}
+		if len(secret) < epicjwt.MinHMACSecretLength {
+			logrus.Warnf("%s is shorter than %d bytes, rotate it to a stronger secret", SecretPublicKeyEnvName, epicjwt.MinHMACSecretLength)
+		}
+		return epicjwt.NewHMAC(secret, epicjwt.HS256), nil
+	}
+
+	public, err := epicjwt.LoadPublicKey(legacyPublicKeyPath)
+	if err != nil {
+		return nil, err
+	}
+	var private *rsa.PrivateKey
+	if _, err := os.Stat(legacyPrivateKeyPath); err == nil {
+		if private, err = epicjwt.LoadPrivateKey(legacyPrivateKeyPath); err != nil {
+			return nil, err
+		}
+	}
+	if public.N.BitLen() < epicjwt.MinRSAKeyBits {
+		logrus.Warnf("%s is shorter than %d bits, rotate it to a stronger key", legacyPublicKeyPath, epicjwt.MinRSAKeyBits)
+	}
+	return epicjwt.NewRSA(private, public, epicjwt.RS256), nil
+}
+
+// Sign issues a token carrying Data, valid for ExpiredAt minutes.
 func Sign(Data map[string]interface{}, SecretPublicKeyEnvName string, ExpiredAt time.Duration) (string, error) {
 	expiredAt := time.Now().Add(time.Duration(time.Minute) * ExpiredAt).Unix()
 
-	jwtSecretKey := GodotEnv(SecretPublicKeyEnvName)
+	j, err := defaultJWT(SecretPublicKeyEnvName)
+	if err != nil {
+		logrus.Error(err.Error())
+		return "", err
+	}
 
 	claims := jwt.MapClaims{}
 	claims["exp"] = expiredAt
@@ -56,9 +169,7 @@ func Sign(Data map[string]interface{}, SecretPublicKeyEnvName string, ExpiredAt
 		claims[i] = v
 	}
 
-	to := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-	accessToken, err := to.SignedString([]byte(jwtSecretKey))
-
+	accessToken, err := j.Sign(claims)
 	if err != nil {
 		logrus.Error(err.Error())
 		return accessToken, err
@@ -67,59 +178,46 @@ func Sign(Data map[string]interface{}, SecretPublicKeyEnvName string, ExpiredAt
 	return accessToken, nil
 }
 
+// VerifyTokenHeader verifies the bearer token of the request with the same
+// EpicJWT as Sign.
 func VerifyTokenHeader(ctx *fiber.Ctx, SecretPublicKeyEnvName string) (*jwt.Token, *VerifiedToken, error) {
 
 	tokenHeader := ctx.Get("Authorization")
 	bearerToken := strings.Split(tokenHeader, "Bearer")
 	if len(bearerToken) < 2 {
-		return nil, nil, errors.New("invalid token format")
+		return nil, nil, epicjwt.ErrMissingToken
 	}
-	accessToken := bearerToken[1]
+	accessToken := strings.TrimSpace(bearerToken[1])
 
-	pubkey, err := loadRSAPublicKey("certs/public.key")
+	j, err := defaultJWT(SecretPublicKeyEnvName)
 	if err != nil {
 		return nil, nil, err
 	}
 
-	token, claims, err := verifyRS256Token(strings.TrimSpace(accessToken), pubkey)
-	if err != nil {
+	claims := &VerifiedToken{}
+	if err := epicjwt.DecodeContext(ctx.UserContext(), j, accessToken, claims); err != nil {
 		logrus.Error(err.Error())
 		return nil, nil, err
 	}
+	claims.Sub = claims.subClaims()
 
-	return token, claims, nil
+	return verifiedToken(accessToken, claims), claims, nil
 
 }
 
-func loadRSAPublicKey(filePath string) (*rsa.PublicKey, error) {
-	keyData, err := os.ReadFile(filePath)
-	if err != nil {
-		log.Println(err)
-		return nil, err
+// verifiedToken wraps claims verified by Decode for the callers of
+// VerifyTokenHeader, with the header when accessToken is a JWS.
+func verifiedToken(accessToken string, claims *VerifiedToken) *jwt.Token {
+	token := &jwt.Token{Raw: accessToken, Claims: claims, Header: map[string]interface{}{}, Valid: true}
+	if header, _, ok := strings.Cut(accessToken, "."); ok {
+		if data, err := jwt.NewParser().DecodeSegment(header); err == nil {
+			json.Unmarshal(data, &token.Header)
+		}
 	}
-
-	pubKey, err := jwt.ParseRSAPublicKeyFromPEM(keyData)
-	if err != nil {
-		log.Println(err)
-		return nil, err
+	if alg, ok := token.Header["alg"].(string); ok {
+		token.Method = jwt.GetSigningMethod(alg)
 	}
-
-	return pubKey, nil
-}
-
-func verifyRS256Token(tokenString string, pubKey *rsa.PublicKey) (*jwt.Token, *VerifiedToken, error) {
-
-	claims := &VerifiedToken{}
-
-	token, err := jwt.ParseWithClaims(tokenString, claims, func(token *jwt.Token) (interface{}, error) {
-		// Ensure the token's signing method is RS256
-		if _, ok := token.Method.(*jwt.SigningMethodRSA); !ok {
-			return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
-		}
-		return pubKey, nil
-	})
-
-	return token, claims, err
+	return token
 }
 
 func JWTProtected() fiber.Handler {
@@ -130,11 +228,14 @@ func JWTProtected() fiber.Handler {
 			return ErrorResponse(c, InvalidToken, "")
 		}
 
-		_, _, err := VerifyTokenHeader(c, "JWT_SECRET_KEY")
+		_, claims, err := VerifyTokenHeader(c, "JWT_SECRET_KEY")
 		if err != nil {
 			log.Println("invalid token")
-			return ErrorResponse(c, InvalidToken, "")
+			return TokenErrorResponse(c, err)
 		}
+
+		// Keep the verified claims for RequireRoles, RequireScopes and RequirePolicy.
+		c.Locals(epicjwt.DefaultContextKey, claims)
 		return c.Status(fiber.StatusOK).Next()
 	}
 }
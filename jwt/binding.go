@@ -0,0 +1,97 @@
+package jwt
+
+import (
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/json"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+	gojwt "github.com/golang-jwt/jwt/v5"
+)
+
+// Binding ties tokens to the device they were issued to, so a stolen token
+// cannot be replayed from elsewhere. Tokens lacking the bound claim are
+// rejected once a check is enabled.
+type Binding struct {
+	// DeviceHeader carries the caller's device id, compared with
+	// DeviceClaim. Empty disables the device check.
+	DeviceHeader string
+
+	// DeviceClaim is the dotted path of the device id in the token.
+	// Default "sub.device_id", where VerifiedToken keeps SubClaims.DeviceID.
+	DeviceClaim string
+
+	// CertThumbprint requires "cnf"."x5t#S256" to match the SHA-256
+	// thumbprint of the mTLS client certificate (RFC 8705).
+	CertThumbprint bool
+
+	// ThumbprintHeader carries the thumbprint when TLS is terminated by a
+	// proxy, it is only trusted when set.
+	ThumbprintHeader string
+}
+
+// CertThumbprint returns the base64url SHA-256 thumbprint of a DER
+// certificate, the value to put in "cnf"."x5t#S256" when issuing a token.
+func CertThumbprint(der []byte) string {
+	sum := sha256.Sum256(der)
+	return base64.RawURLEncoding.EncodeToString(sum[:])
+}
+
+// check compares the request fingerprint with claims.
+func (b *Binding) check(c *fiber.Ctx, claims gojwt.Claims) error {
+	data, err := json.Marshal(claims)
+	if err != nil {
+		return err
+	}
+	var payload map[string]any
+	if err := json.Unmarshal(data, &payload); err != nil {
+		return err
+	}
+	jti, _ := payload["jti"].(string)
+
+	if b.DeviceHeader != "" {
+		claim := b.DeviceClaim
+		if claim == "" {
+			claim = "sub.device_id"
+		}
+		if !matches(lookupClaim(payload, claim), c.Get(b.DeviceHeader)) {
+			return &VerificationError{Reason: ErrTokenBindingMismatch, Claim: claim, TokenID: jti}
+		}
+	}
+
+	if b.CertThumbprint {
+		if !matches(lookupClaim(payload, "cnf.x5t#S256"), b.thumbprint(c)) {
+			return &VerificationError{Reason: ErrTokenBindingMismatch, Claim: "cnf", TokenID: jti}
+		}
+	}
+	return nil
+}
+
+func (b *Binding) thumbprint(c *fiber.Ctx) string {
+	if state := c.Context().TLSConnectionState(); state != nil && len(state.PeerCertificates) > 0 {
+		return CertThumbprint(state.PeerCertificates[0].Raw)
+	}
+	if b.ThumbprintHeader != "" {
+		return c.Get(b.ThumbprintHeader)
+	}
+	return ""
+}
+
+func lookupClaim(payload map[string]any, path string) string {
+	var value any = payload
+	for _, key := range strings.Split(path, ".") {
+		object, ok := value.(map[string]any)
+		if !ok {
+			return ""
+		}
+		value = object[key]
+	}
+	s, _ := value.(string)
+	return s
+}
+
+func matches(bound, presented string) bool {
+	return bound != "" && subtle.ConstantTimeCompare([]byte(bound), []byte(presented)) == 1
+}
@@ -0,0 +1,58 @@
+package jwt
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"errors"
+
+	gojwt "github.com/golang-jwt/jwt/v5"
+)
+
+type epicECDSA struct {
+	algorithm  gojwt.SigningMethod
+	privateKey *ecdsa.PrivateKey
+	publicKey  *ecdsa.PublicKey
+	opts       options
+}
+
+// NewECDSA does not check the key curve, New rejects curves not mandated by
+// algorithm.
+func NewECDSA(private *ecdsa.PrivateKey, public *ecdsa.PublicKey, algorithm gojwt.SigningMethod, opts ...Option) EpicJWT {
+	return &epicECDSA{
+		algorithm:  algorithm,
+		privateKey: private,
+		publicKey:  public,
+		opts:       newOptions(opts),
+	}
+}
+
+func (e *epicECDSA) Sign(claims gojwt.Claims) (string, error) {
+	if e.privateKey == nil {
+		return "", errors.New("requires private key to sign jwt")
+	}
+	token := gojwt.NewWithClaims(e.algorithm, claims)
+	signedToken, err := token.SignedString(e.privateKey)
+	if err != nil {
+		return "", err
+	}
+	return signedToken, nil
+}
+
+func (e *epicECDSA) Verify(tokenStr string) error {
+	return e.opts.parse(context.Background(), tokenStr, gojwt.MapClaims{}, e.keyFunc)
+}
+
+func (e *epicECDSA) Decode(tokenStr string, claims gojwt.Claims) error {
+	return e.DecodeContext(context.Background(), tokenStr, claims)
+}
+
+func (e *epicECDSA) DecodeContext(ctx context.Context, tokenStr string, claims gojwt.Claims) error {
+	return e.opts.parse(ctx, tokenStr, claims, e.keyFunc)
+}
+
+func (e *epicECDSA) keyFunc(token *gojwt.Token) (any, error) {
+	if _, ok := token.Method.(*gojwt.SigningMethodECDSA); !ok {
+		return nil, unexpectedMethod(token)
+	}
+	return e.publicKey, nil
+}
@@ -0,0 +1,54 @@
+package jwt
+
+import (
+	"context"
+	"crypto/ed25519"
+	"errors"
+
+	gojwt "github.com/golang-jwt/jwt/v5"
+)
+
+type epicEdDSA struct {
+	privateKey ed25519.PrivateKey
+	publicKey  ed25519.PublicKey
+	opts       options
+}
+
+func NewEdDSA(private ed25519.PrivateKey, public ed25519.PublicKey, opts ...Option) EpicJWT {
+	return &epicEdDSA{
+		privateKey: private,
+		publicKey:  public,
+		opts:       newOptions(opts),
+	}
+}
+
+func (e *epicEdDSA) Sign(claims gojwt.Claims) (string, error) {
+	if e.privateKey == nil {
+		return "", errors.New("requires private key to sign jwt")
+	}
+	token := gojwt.NewWithClaims(EdDSA, claims)
+	signedToken, err := token.SignedString(e.privateKey)
+	if err != nil {
+		return "", err
+	}
+	return signedToken, nil
+}
+
+func (e *epicEdDSA) Verify(tokenStr string) error {
+	return e.opts.parse(context.Background(), tokenStr, gojwt.MapClaims{}, e.keyFunc)
+}
+
+func (e *epicEdDSA) Decode(tokenStr string, claims gojwt.Claims) error {
+	return e.DecodeContext(context.Background(), tokenStr, claims)
+}
+
+func (e *epicEdDSA) DecodeContext(ctx context.Context, tokenStr string, claims gojwt.Claims) error {
+	return e.opts.parse(ctx, tokenStr, claims, e.keyFunc)
+}
+
+func (e *epicEdDSA) keyFunc(token *gojwt.Token) (any, error) {
+	if _, ok := token.Method.(*gojwt.SigningMethodEd25519); !ok {
+		return nil, unexpectedMethod(token)
+	}
+	return e.publicKey, nil
+}
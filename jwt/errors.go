@@ -0,0 +1,79 @@
+package jwt
+
+import (
+	"errors"
+	"fmt"
+
+	gojwt "github.com/golang-jwt/jwt/v5"
+)
+
+// VerificationError explains why a token was rejected. errors.Is matches
+// Reason, so checks against the Err* sentinels keep working.
+//
+//	var verr *jwt.VerificationError
+//	if errors.As(err, &verr) && verr.Claim == jwt.ClaimExpiration { ... }
+type VerificationError struct {
+	Reason  error  // one of the Err* sentinels
+	Claim   string // offending claim, e.g. "exp" or "aud", empty if none
+	TokenID string // "jti" of the rejected token, unverified unless Reason is a claim check
+	Err     error  // underlying cause, may be nil
+}
+
+func (e *VerificationError) Error() string {
+	if e.Claim != "" {
+		return fmt.Sprintf("%s (%s)", e.Reason, e.Claim)
+	}
+	return e.Reason.Error()
+}
+
+func (e *VerificationError) Unwrap() []error {
+	if e.Err == nil {
+		return []error{e.Reason}
+	}
+	return []error{e.Reason, e.Err}
+}
+
+func reject(reason error, claim string) *VerificationError {
+	return &VerificationError{Reason: reason, Claim: claim}
+}
+
+// mapError translates golang-jwt errors into a VerificationError. Errors not
+// caused by the token, e.g. a JWKS that cannot be fetched, are returned as is.
+func mapError(err error) error {
+	var verr *VerificationError
+	switch {
+	case errors.As(err, &verr):
+		return verr
+	case errors.Is(err, gojwt.ErrTokenExpired):
+		verr = reject(ErrTokenExpired, ClaimExpiration)
+	case errors.Is(err, gojwt.ErrTokenMalformed):
+		verr = reject(ErrTokenMalformed, "")
+	case errors.Is(err, gojwt.ErrTokenNotValidYet):
+		verr = reject(ErrTokenNotValidYet, "nbf")
+	case errors.Is(err, gojwt.ErrTokenUsedBeforeIssued):
+		verr = reject(ErrTokenUsedBeforeIssued, ClaimIssuedAt)
+	case errors.Is(err, gojwt.ErrTokenInvalidIssuer):
+		verr = reject(ErrTokenInvalidIssuer, "iss")
+	case errors.Is(err, gojwt.ErrTokenRequiredClaimMissing):
+		verr = reject(ErrTokenRequiredClaimMissing, "")
+	case errors.Is(err, gojwt.ErrTokenSignatureInvalid):
+		verr = reject(ErrTokenSignatureInvalid, "")
+	case errors.Is(err, ErrUnexpectedSigningMethod):
+		verr = reject(ErrUnexpectedSigningMethod, "")
+	case errors.Is(err, ErrUnknownKeyID):
+		verr = reject(ErrUnknownKeyID, "")
+	default:
+		return fmt.Errorf("failed to parse token: %w", err)
+	}
+	verr.Err = err
+	return verr
+}
+
+// withTokenID stamps jti onto err if it is a VerificationError.
+func withTokenID(err error, jti string) error {
+	var verr *VerificationError
+	if jti != "" && errors.As(err, &verr) && verr.TokenID == "" {
+		verr.TokenID = jti
+	}
+	return err
+}
@@ -0,0 +1,115 @@
+package jwt
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"time"
+
+	gojwt "github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+)
+
+// Actor is the party acting on behalf of the token subject, the "act" claim
+// of RFC 8693. Act holds the previous actor when impersonation is chained.
+type Actor struct {
+	Subject string `json:"sub"`
+	Act     *Actor `json:"act,omitempty"`
+}
+
+type ExchangeConfig struct {
+	TTL    time.Duration // default 15 minutes, never beyond the actor token
+	Issuer string
+
+	// Authorize decides whether actor may act as subject, e.g. forbidding
+	// the impersonation of admins. Required.
+	Authorize func(ctx context.Context, actor gojwt.Claims, subject string) error
+
+	// Claims builds the claims of the minted token. registered is
+	// pre-filled with jti, sub, iss, iat and exp and act must be stored in
+	// the "act" claim. Default ActorClaims.
+	Claims func(ctx context.Context, registered gojwt.RegisteredClaims, act *Actor) (gojwt.Claims, error)
+}
+
+// ActorClaims are the default claims of an exchanged token. Their "sub" is
+// the subject string, services decoding tokens into pkgep.VerifiedToken
+// expect the SubClaims object instead and need a Claims factory building it.
+type ActorClaims struct {
+	Act *Actor `json:"act,omitempty"`
+	gojwt.RegisteredClaims
+}
+
+var ErrExchangeDenied = errors.New("token exchange is not allowed")
+
+// Exchanger mints short-lived tokens acting as another subject, recording
+// the caller in "act" so every request made with them can be audited.
+type Exchanger struct {
+	jwt EpicJWT
+	cfg ExchangeConfig
+}
+
+func NewExchanger(jwt EpicJWT, cfg ExchangeConfig) (*Exchanger, error) {
+	if cfg.Authorize == nil {
+		return nil, errors.New("requires authorize")
+	}
+	if cfg.TTL == 0 {
+		cfg.TTL = 15 * time.Minute
+	}
+	if cfg.Claims == nil {
+		cfg.Claims = func(_ context.Context, registered gojwt.RegisteredClaims, act *Actor) (gojwt.Claims, error) {
+			return ActorClaims{Act: act, RegisteredClaims: registered}, nil
+		}
+	}
+	return &Exchanger{jwt: jwt, cfg: cfg}, nil
+}
+
+// Exchange mints a token for subject on behalf of actor, the verified claims
+// of the caller. Exchanged tokens cannot be exchanged again.
+func (e *Exchanger) Exchange(ctx context.Context, actor gojwt.Claims, subject string) (string, error) {
+	actorSubject, err := actor.GetSubject()
+	if err != nil || actorSubject == "" {
+		return "", reject(ErrTokenRequiredClaimMissing, ClaimSubject)
+	}
+	if subject == "" || subject == actorSubject || ActorOf(actor) != nil {
+		return "", ErrExchangeDenied
+	}
+	if err := e.cfg.Authorize(ctx, actor, subject); err != nil {
+		return "", err
+	}
+
+	act := &Actor{Subject: actorSubject}
+
+	now := time.Now()
+	expiresAt := now.Add(e.cfg.TTL)
+	if exp, _ := actor.GetExpirationTime(); exp != nil && exp.Before(expiresAt) {
+		expiresAt = exp.Time
+	}
+
+	claims, err := e.cfg.Claims(ctx, gojwt.RegisteredClaims{
+		ID:        uuid.NewString(),
+		Subject:   subject,
+		Issuer:    e.cfg.Issuer,
+		IssuedAt:  gojwt.NewNumericDate(now),
+		ExpiresAt: gojwt.NewNumericDate(expiresAt),
+	}, act)
+	if err != nil {
+		return "", err
+	}
+	return e.jwt.Sign(claims)
+}
+
+// ActorOf returns the "act" claim of claims, nil unless the token was
+// obtained through Exchange.
+func ActorOf(claims gojwt.Claims) *Actor {
+	data, err := json.Marshal(claims)
+	if err != nil {
+		return nil
+	}
+	var act struct {
+		Act *Actor `json:"act"`
+	}
+	if err := json.Unmarshal(data, &act); err != nil {
+		return nil
+	}
+	return act.Act
+}
@@ -0,0 +1,148 @@
+package jwt
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// Family is a chain of refresh tokens issued from a single login. Only the
+// Current refresh token id may be exchanged.
+type Family struct {
+	ID        string
+	Subject   string
+	Current   string
+	ExpiresAt time.Time
+}
+
+// FamilyStore persists refresh token families.
+type FamilyStore interface {
+	Create(ctx context.Context, family Family) error
+
+	// Rotate replaces the current refresh token id of family.ID with
+	// family.Current, provided previous is still the current one. Otherwise
+	// the family is revoked and ErrRefreshTokenReused is returned.
+	// Unknown, expired or revoked families yield ErrRefreshTokenRevoked.
+	Rotate(ctx context.Context, family Family, previous string) error
+
+	Revoke(ctx context.Context, familyID string) error
+}
+
+type memoryFamilyStore struct {
+	mu       sync.Mutex
+	families map[string]Family
+}
+
+func NewMemoryFamilyStore() FamilyStore {
+	return &memoryFamilyStore{families: map[string]Family{}}
+}
+
+func (m *memoryFamilyStore) Create(_ context.Context, family Family) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.prune()
+	m.families[family.ID] = family
+	return nil
+}
+
+func (m *memoryFamilyStore) Rotate(_ context.Context, family Family, previous string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.prune()
+	stored, ok := m.families[family.ID]
+	if !ok {
+		return ErrRefreshTokenRevoked
+	}
+	if stored.Current != previous {
+		delete(m.families, family.ID)
+		return ErrRefreshTokenReused
+	}
+
+	stored.Current = family.Current
+	stored.ExpiresAt = family.ExpiresAt
+	m.families[family.ID] = stored
+	return nil
+}
+
+func (m *memoryFamilyStore) Revoke(_ context.Context, familyID string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	delete(m.families, familyID)
+	return nil
+}
+
+// prune drops expired families, caller must hold the lock.
+func (m *memoryFamilyStore) prune() {
+	now := time.Now()
+	for id, family := range m.families {
+		if now.After(family.ExpiresAt) {
+			delete(m.families, id)
+		}
+	}
+}
+
+// Compare-and-swap of the current refresh token id, revoking the family on
+// mismatch. Returns 1 on success, 0 for unknown family and -1 on reuse.
+var rotateScript = redis.NewScript(`
+local current = redis.call('HGET', KEYS[1], 'current')
+if not current then
+	return 0
+end
+if current ~= ARGV[1] then
+	redis.call('DEL', KEYS[1])
+	return -1
+end
+redis.call('HSET', KEYS[1], 'current', ARGV[2])
+redis.call('PEXPIREAT', KEYS[1], ARGV[3])
+return 1
+`)
+
+type redisFamilyStore struct {
+	client redis.UniversalClient
+	prefix string
+}
+
+// NewRedisFamilyStore keeps each family in a hash named prefix+familyID that
+// expires together with its latest refresh token.
+func NewRedisFamilyStore(client redis.UniversalClient, prefix string) FamilyStore {
+	return &redisFamilyStore{client: client, prefix: prefix}
+}
+
+func (r *redisFamilyStore) Create(ctx context.Context, family Family) error {
+	key := r.prefix + family.ID
+
+	_, err := r.client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.HSet(ctx, key, "subject", family.Subject, "current", family.Current)
+		pipe.ExpireAt(ctx, key, family.ExpiresAt)
+		return nil
+	})
+	return err
+}
+
+func (r *redisFamilyStore) Rotate(ctx context.Context, family Family, previous string) error {
+	result, err := rotateScript.Run(ctx, r.client,
+		[]string{r.prefix + family.ID},
+		previous, family.Current, family.ExpiresAt.UnixMilli(),
+	).Int()
+	if err != nil {
+		return err
+	}
+
+	switch result {
+	case 0:
+		return ErrRefreshTokenRevoked
+	case -1:
+		return ErrRefreshTokenReused
+	default:
+		return nil
+	}
+}
+
+func (r *redisFamilyStore) Revoke(ctx context.Context, familyID string) error {
+	return r.client.Del(ctx, r.prefix+familyID).Err()
+}
@@ -1,9 +1,12 @@
 package jwt
 
 import (
+	"crypto/ecdsa"
+	"crypto/ed25519"
 	"crypto/rsa"
 	"encoding/pem"
 	"errors"
+	"fmt"
 	"os"
 
 	gojwt "github.com/golang-jwt/jwt/v5"
@@ -49,3 +52,63 @@ func LoadPrivateKey(path string) (*rsa.PrivateKey, error) {
 
 	return priKey, nil
 }
+
+// Load ECDSA public key from file
+func LoadECPublicKey(path string) (*ecdsa.PublicKey, error) {
+	keyData, err := readPEM(path, "public")
+	if err != nil {
+		return nil, err
+	}
+	return gojwt.ParseECPublicKeyFromPEM(keyData)
+}
+
+// Load ECDSA private key (SEC 1 or PKCS #8) from file
+func LoadECPrivateKey(path string) (*ecdsa.PrivateKey, error) {
+	keyData, err := readPEM(path, "private")
+	if err != nil {
+		return nil, err
+	}
+	return gojwt.ParseECPrivateKeyFromPEM(keyData)
+}
+
+// Load Ed25519 public key from file
+func LoadEdPublicKey(path string) (ed25519.PublicKey, error) {
+	keyData, err := readPEM(path, "public")
+	if err != nil {
+		return nil, err
+	}
+
+	pubKey, err := gojwt.ParseEdPublicKeyFromPEM(keyData)
+	if err != nil {
+		return nil, err
+	}
+	return pubKey.(ed25519.PublicKey), nil
+}
+
+// Load Ed25519 private key (PKCS #8) from file
+func LoadEdPrivateKey(path string) (ed25519.PrivateKey, error) {
+	keyData, err := readPEM(path, "private")
+	if err != nil {
+		return nil, err
+	}
+
+	priKey, err := gojwt.ParseEdPrivateKeyFromPEM(keyData)
+	if err != nil {
+		return nil, err
+	}
+	return priKey.(ed25519.PrivateKey), nil
+}
+
+// readPEM reads a file and makes sure it holds a PEM block.
+func readPEM(path string, kind string) ([]byte, error) {
+	keyData, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	block, _ := pem.Decode(keyData)
+	if block == nil {
+		return nil, fmt.Errorf("failed to parse PEM block containing the %s key", kind)
+	}
+	return keyData, nil
+}
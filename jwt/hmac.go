@@ -1,8 +1,7 @@
 package jwt
 
 import (
-	"errors"
-	"fmt"
+	"context"
 
 	gojwt "github.com/golang-jwt/jwt/v5"
 )
@@ -10,10 +9,13 @@ import (
 type epicHmac struct {
 	algorithm gojwt.SigningMethod
 	secret    string
+	opts      options
 }
 
-func NewHMAC(secret string, algorithm gojwt.SigningMethod) EpicJWT {
-	return &epicHmac{secret: secret, algorithm: algorithm}
+// NewHMAC does not check the secret length, New rejects secrets shorter
+// than MinHMACSecretLength or the hash size of algorithm.
+func NewHMAC(secret string, algorithm gojwt.SigningMethod, opts ...Option) EpicJWT {
+	return &epicHmac{secret: secret, algorithm: algorithm, opts: newOptions(opts)}
 }
 
 func (h *epicHmac) Sign(claims gojwt.Claims) (string, error) {
@@ -26,64 +28,20 @@ func (h *epicHmac) Sign(claims gojwt.Claims) (string, error) {
 }
 
 func (h *epicHmac) Verify(tokenStr string) error {
-	token, err := gojwt.Parse(tokenStr, func(token *gojwt.Token) (any, error) {
-		if _, ok := token.Method.(*gojwt.SigningMethodHMAC); !ok {
-			return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
-		}
-		return []byte(h.secret), nil
-	})
-
-	if err != nil {
-		switch {
-		case errors.Is(err, gojwt.ErrTokenExpired):
-			return ErrTokenExpired
-		case errors.Is(err, gojwt.ErrTokenMalformed):
-			return ErrTokenMalformed
-		case errors.Is(err, gojwt.ErrTokenNotValidYet):
-			return ErrTokenNotValidYet
-		case errors.Is(err, gojwt.ErrTokenSignatureInvalid):
-			return ErrTokenSignatureInvalid
-		case errors.Is(err, ErrUnexpectedSigningMethod):
-			return ErrUnexpectedSigningMethod
-		default:
-			return fmt.Errorf("failed to parse token: %w", err)
-		}
-	}
-
-	if !token.Valid {
-		return ErrInvalidToken
-	}
-	return nil
+	return h.opts.parse(context.Background(), tokenStr, gojwt.MapClaims{}, h.keyFunc)
 }
 
 func (h *epicHmac) Decode(tokenStr string, claims gojwt.Claims) error {
-	token, err := gojwt.ParseWithClaims(tokenStr, claims, func(token *gojwt.Token) (any, error) {
-		if _, ok := token.Method.(*gojwt.SigningMethodHMAC); !ok {
-			return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
-		}
-		return []byte(h.secret), nil
-	})
+	return h.DecodeContext(context.Background(), tokenStr, claims)
+}
 
-	if err != nil {
-		switch {
-		case errors.Is(err, gojwt.ErrTokenExpired):
-			return ErrTokenExpired
-		case errors.Is(err, gojwt.ErrTokenMalformed):
-			return ErrTokenMalformed
-		case errors.Is(err, gojwt.ErrTokenNotValidYet):
-			return ErrTokenNotValidYet
-		case errors.Is(err, gojwt.ErrTokenSignatureInvalid):
-			return ErrTokenSignatureInvalid
-		case errors.Is(err, ErrUnexpectedSigningMethod):
-			return ErrUnexpectedSigningMethod
-		default:
-			return fmt.Errorf("failed to parse token: %w", err)
-		}
-	}
+func (h *epicHmac) DecodeContext(ctx context.Context, tokenStr string, claims gojwt.Claims) error {
+	return h.opts.parse(ctx, tokenStr, claims, h.keyFunc)
+}
 
-	if !token.Valid {
-		return ErrInvalidToken
+func (h *epicHmac) keyFunc(token *gojwt.Token) (any, error) {
+	if _, ok := token.Method.(*gojwt.SigningMethodHMAC); !ok {
+		return nil, unexpectedMethod(token)
 	}
-
-	return nil
+	return []byte(h.secret), nil
 }
@@ -0,0 +1,147 @@
+package jwt
+
+import (
+	"context"
+	"path/filepath"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+	gojwt "github.com/golang-jwt/jwt/v5"
+)
+
+// Delay after the last file event before rebuilding, so a secret rotation
+// touching several files is picked up in one go.
+const hotSwapDebounce = 500 * time.Millisecond
+
+// HotSwapJWT rebuilds its EpicJWT whenever one of the watched key files
+// changes, so rotated keys are used without a restart. A failed rebuild
+// keeps the previous EpicJWT.
+type HotSwapJWT struct {
+	current atomic.Pointer[EpicJWT]
+	build   func() (EpicJWT, error)
+	onError func(error)
+	watcher *fsnotify.Watcher
+	done    chan struct{}
+	once    sync.Once
+}
+
+// NewHotSwap builds an EpicJWT with build and rebuilds it when any of paths
+// changes. Parent directories are watched so Kubernetes secret updates,
+// which swap a symlink, are noticed too. onError may be nil.
+//
+//	j, err := jwt.NewHotSwap(func() (jwt.EpicJWT, error) {
+//		key, err := jwt.LoadPrivateKeyFrom(jwt.FileKey("/secrets/jwt.pem"), nil)
+//		...
+//	}, nil, "/secrets/jwt.pem")
+func NewHotSwap(build func() (EpicJWT, error), onError func(error), paths ...string) (*HotSwapJWT, error) {
+	h := &HotSwapJWT{
+		build:   build,
+		onError: onError,
+		done:    make(chan struct{}),
+	}
+	if err := h.Reload(); err != nil {
+		return nil, err
+	}
+
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return nil, err
+	}
+	dirs := map[string]bool{}
+	for _, path := range paths {
+		dir := filepath.Dir(path)
+		if dirs[dir] {
+			continue
+		}
+		if err := watcher.Add(dir); err != nil {
+			watcher.Close()
+			return nil, err
+		}
+		dirs[dir] = true
+	}
+	h.watcher = watcher
+
+	go h.watch()
+	return h, nil
+}
+
+// Reload rebuilds the EpicJWT immediately.
+func (h *HotSwapJWT) Reload() error {
+	j, err := h.build()
+	if err != nil {
+		return err
+	}
+	h.current.Store(&j)
+	return nil
+}
+
+// Close stops watching the key files.
+func (h *HotSwapJWT) Close() error {
+	var err error
+	h.once.Do(func() {
+		close(h.done)
+		err = h.watcher.Close()
+	})
+	return err
+}
+
+func (h *HotSwapJWT) watch() {
+	var timer *time.Timer
+	for {
+		select {
+		case <-h.done:
+			if timer != nil {
+				timer.Stop()
+			}
+			return
+		case _, ok := <-h.watcher.Events:
+			if !ok {
+				return
+			}
+			if timer == nil {
+				timer = time.AfterFunc(hotSwapDebounce, h.reload)
+			} else {
+				timer.Reset(hotSwapDebounce)
+			}
+		case err, ok := <-h.watcher.Errors:
+			if !ok {
+				return
+			}
+			h.report(err)
+		}
+	}
+}
+
+func (h *HotSwapJWT) reload() {
+	if err := h.Reload(); err != nil {
+		h.report(err)
+	}
+}
+
+func (h *HotSwapJWT) report(err error) {
+	if h.onError != nil {
+		h.onError(err)
+	}
+}
+
+func (h *HotSwapJWT) load() EpicJWT {
+	return *h.current.Load()
+}
+
+func (h *HotSwapJWT) Sign(claims gojwt.Claims) (string, error) {
+	return h.load().Sign(claims)
+}
+
+func (h *HotSwapJWT) Verify(tokenStr string) error {
+	return h.load().Verify(tokenStr)
+}
+
+func (h *HotSwapJWT) Decode(tokenStr string, claims gojwt.Claims) error {
+	return h.load().Decode(tokenStr, claims)
+}
+
+func (h *HotSwapJWT) DecodeContext(ctx context.Context, tokenStr string, claims gojwt.Claims) error {
+	return DecodeContext(ctx, h.load(), tokenStr, claims)
+}
@@ -1,8 +1,14 @@
 package jwt
 
 import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
 	"crypto/rsa"
 	"errors"
+	"fmt"
+	"time"
 
 	gojwt "github.com/golang-jwt/jwt/v5"
 )
@@ -12,39 +18,228 @@ type Config struct {
 	Secret     string
 	PublicKey  *rsa.PublicKey
 	PrivateKey *rsa.PrivateKey
+
+	ECPublicKey  *ecdsa.PublicKey
+	ECPrivateKey *ecdsa.PrivateKey
+	EdPublicKey  ed25519.PublicKey
+	EdPrivateKey ed25519.PrivateKey
+
+	// Signer signs RS*, PS* and ES* tokens in place of the private key
+	// fields, e.g. an HSM or KMS backed key. Its public key verifies.
+	Signer crypto.Signer
+
+	// Keys enables key rotation: tokens are signed with KeyID stamped in the
+	// "kid" header and verified against any key of the set.
+	KeyID string
+	Keys  KeyProvider
+
+	// Claim validation applied by every implementation.
+	Issuer         string           // expected "iss", ignored when empty
+	Audience       []string         // accepted "aud" values, any of them matches
+	Leeway         time.Duration    // tolerated clock skew
+	RequiredClaims []string         // ClaimExpiration, ClaimIssuedAt and/or ClaimSubject
+	Now            func() time.Time // clock used for time based claims, defaults to time.Now
+
+	// Revocation rejects tokens denied by jti or subject before their "exp".
+	Revocation RevocationStore
+
+	// Encryption wraps signed tokens in a JWE, see NewNested.
+	Encryption *Encryption
+
+	// WeakKey accepts secrets and RSA keys below MinHMACSecretLength and
+	// MinRSAKeyBits, only for legacy keys that cannot be rotated yet.
+	WeakKey bool
 }
 
 // type Algorithm int
 
 var (
 	HS256 = gojwt.SigningMethodHS256
+	HS384 = gojwt.SigningMethodHS384
+	HS512 = gojwt.SigningMethodHS512
 	RS256 = gojwt.SigningMethodRS256
+	RS384 = gojwt.SigningMethodRS384
+	RS512 = gojwt.SigningMethodRS512
+	PS256 = gojwt.SigningMethodPS256
+	PS384 = gojwt.SigningMethodPS384
+	PS512 = gojwt.SigningMethodPS512
+	ES256 = gojwt.SigningMethodES256
+	ES384 = gojwt.SigningMethodES384
+	ES512 = gojwt.SigningMethodES512
+	EdDSA = gojwt.SigningMethodEdDSA
+)
+
+const (
+	// MinHMACSecretLength is the minimum HS256 secret length in bytes.
+	// HS384 and HS512 require at least as many bytes as their hash output.
+	MinHMACSecretLength = 32
+
+	// MinRSAKeyBits is the minimum RSA modulus size for RS* and PS* algorithms.
+	MinRSAKeyBits = 2048
 )
 
 func New(cfg Config) (EpicJWT, error) {
-	switch cfg.Algorithm {
-	case HS256:
+	if cfg.Signer != nil {
+		switch public := cfg.Signer.Public().(type) {
+		case *rsa.PublicKey:
+			cfg.PublicKey = public
+		case *ecdsa.PublicKey:
+			cfg.ECPublicKey = public
+		}
+	}
+	if err := checkStrength(cfg); err != nil {
+		return nil, err
+	}
+
+	j, err := newSigner(cfg)
+	if err != nil || cfg.Encryption == nil {
+		return j, err
+	}
+	return NewNested(j, *cfg.Encryption)
+}
+
+func newSigner(cfg Config) (EpicJWT, error) {
+	if cfg.Keys != nil {
+		return newKeyringFromConfig(cfg)
+	}
+
+	switch cfg.Algorithm.(type) {
+	case *gojwt.SigningMethodHMAC:
 		if cfg.Secret == "" {
 			return nil, errors.New("requires secret")
 		}
-		return NewHMAC(cfg.Secret, cfg.Algorithm), nil
-	case RS256:
+		return NewHMAC(cfg.Secret, cfg.Algorithm, cfg.options()...), nil
+	case *gojwt.SigningMethodRSA, *gojwt.SigningMethodRSAPSS:
+		if cfg.Signer != nil {
+			return NewSigner(cfg.Signer, cfg.Algorithm, cfg.options()...)
+		}
 		if cfg.PublicKey == nil {
 			return nil, errors.New("requires public key")
 		}
-		return NewRSA(cfg.PrivateKey, cfg.PublicKey, cfg.Algorithm), nil
+		return NewRSA(cfg.PrivateKey, cfg.PublicKey, cfg.Algorithm, cfg.options()...), nil
+	case *gojwt.SigningMethodECDSA:
+		if cfg.Signer != nil {
+			return NewSigner(cfg.Signer, cfg.Algorithm, cfg.options()...)
+		}
+		if cfg.ECPublicKey == nil {
+			return nil, errors.New("requires public key")
+		}
+		return NewECDSA(cfg.ECPrivateKey, cfg.ECPublicKey, cfg.Algorithm, cfg.options()...), nil
+	case *gojwt.SigningMethodEd25519:
+		if cfg.EdPublicKey == nil {
+			return nil, errors.New("requires public key")
+		}
+		return NewEdDSA(cfg.EdPrivateKey, cfg.EdPublicKey, cfg.options()...), nil
+	case *PasetoMethod:
+		return newPaseto(cfg)
 	default:
 		return nil, errors.New("unsupported algorithm")
 	}
 }
 
+// checkStrength rejects keys that are too weak for the configured algorithm.
+// The ECDSA curve is checked even with WeakKey, a mismatch cannot verify.
+func checkStrength(cfg Config) error {
+	switch method := cfg.Algorithm.(type) {
+	case *gojwt.SigningMethodHMAC:
+		if cfg.WeakKey {
+			break
+		}
+		minLength := max(MinHMACSecretLength, method.Hash.Size())
+		if cfg.Secret != "" && len(cfg.Secret) < minLength {
+			return fmt.Errorf("%w: %s requires a secret of at least %d bytes", ErrWeakKey, method.Alg(), minLength)
+		}
+	case *gojwt.SigningMethodRSA, *gojwt.SigningMethodRSAPSS:
+		if cfg.WeakKey {
+			break
+		}
+		if cfg.PublicKey != nil && cfg.PublicKey.N.BitLen() < MinRSAKeyBits {
+			return fmt.Errorf("%w: %s requires a key of at least %d bits", ErrWeakKey, method.Alg(), MinRSAKeyBits)
+		}
+		if cfg.PrivateKey != nil && cfg.PrivateKey.N.BitLen() < MinRSAKeyBits {
+			return fmt.Errorf("%w: %s requires a key of at least %d bits", ErrWeakKey, method.Alg(), MinRSAKeyBits)
+		}
+	case *gojwt.SigningMethodECDSA:
+		if cfg.ECPublicKey != nil {
+			return checkCurve(cfg.ECPublicKey, method)
+		}
+	}
+	return nil
+}
+
+// checkCurve makes sure the key curve is the one mandated by the algorithm,
+// e.g. P-256 for ES256.
+func checkCurve(key *ecdsa.PublicKey, method *gojwt.SigningMethodECDSA) error {
+	if key.Curve.Params().BitSize != method.CurveBits {
+		return fmt.Errorf("%s requires a %d-bit curve", method.Alg(), method.CurveBits)
+	}
+	return nil
+}
+
+func newPaseto(cfg Config) (EpicJWT, error) {
+	if cfg.Algorithm == PasetoV4Local {
+		if cfg.Secret == "" {
+			return nil, errors.New("requires secret")
+		}
+		return NewPasetoLocal([]byte(cfg.Secret), cfg.options()...)
+	}
+	if cfg.EdPublicKey == nil {
+		return nil, errors.New("requires public key")
+	}
+	return NewPasetoPublic(cfg.EdPrivateKey, cfg.EdPublicKey, cfg.options()...), nil
+}
+
+func newKeyringFromConfig(cfg Config) (EpicJWT, error) {
+	var key any
+	switch cfg.Algorithm.(type) {
+	case *gojwt.SigningMethodHMAC:
+		if cfg.Secret != "" {
+			key = []byte(cfg.Secret)
+		}
+	case *gojwt.SigningMethodRSA, *gojwt.SigningMethodRSAPSS:
+		if cfg.Signer != nil {
+			key = cfg.Signer
+		} else if cfg.PrivateKey != nil {
+			key = cfg.PrivateKey
+		}
+	case *gojwt.SigningMethodECDSA:
+		if cfg.Signer != nil {
+			key = cfg.Signer
+		} else if cfg.ECPrivateKey != nil {
+			key = cfg.ECPrivateKey
+		}
+	case *gojwt.SigningMethodEd25519:
+		if cfg.EdPrivateKey != nil {
+			key = cfg.EdPrivateKey
+		}
+	default:
+		return nil, errors.New("unsupported algorithm")
+	}
+
+	if key == nil {
+		return NewKeyring(nil, cfg.Keys, cfg.options()...), nil
+	}
+	if cfg.KeyID == "" {
+		return nil, errors.New("requires key id")
+	}
+	return NewKeyring(&SigningKey{ID: cfg.KeyID, Algorithm: cfg.Algorithm, Key: key}, cfg.Keys, cfg.options()...), nil
+}
+
 var (
-	ErrTokenExpired            = errors.New("token is expired")
-	ErrInvalidToken            = errors.New("token is invalid")
-	ErrTokenMalformed          = errors.New("token is malformed")
-	ErrTokenNotValidYet        = errors.New("token is not valid yet")
-	ErrTokenSignatureInvalid   = errors.New("token signature is invalid")
-	ErrUnexpectedSigningMethod = errors.New("token signing method deos not match")
+	ErrTokenExpired              = errors.New("token is expired")
+	ErrInvalidToken              = errors.New("token is invalid")
+	ErrTokenMalformed            = errors.New("token is malformed")
+	ErrTokenNotValidYet          = errors.New("token is not valid yet")
+	ErrTokenSignatureInvalid     = errors.New("token signature is invalid")
+	ErrUnexpectedSigningMethod   = errors.New("token signing method deos not match")
+	ErrUnknownKeyID              = errors.New("token key id is unknown")
+	ErrWeakKey                   = errors.New("key does not meet the minimum strength")
+	ErrTokenInvalidIssuer        = errors.New("token has invalid issuer")
+	ErrTokenInvalidAudience      = errors.New("token has invalid audience")
+	ErrTokenUsedBeforeIssued     = errors.New("token used before issued")
+	ErrTokenRequiredClaimMissing = errors.New("token is missing required claim")
+	ErrTokenRevoked              = errors.New("token has been revoked")
+	ErrTokenBindingMismatch      = errors.New("token is bound to another device")
 )
 
 type EpicJWT interface {
@@ -52,3 +247,19 @@ type EpicJWT interface {
 	Verify(token string) error
 	Decode(token string, claims gojwt.Claims) error
 }
+
+// ContextDecoder is implemented by the EpicJWTs of this package, so store
+// lookups made while decoding, e.g. WithRevocation, run with the caller's
+// context instead of context.Background.
+type ContextDecoder interface {
+	DecodeContext(ctx context.Context, token string, claims gojwt.Claims) error
+}
+
+// DecodeContext decodes token with ctx when j is a ContextDecoder, with
+// j.Decode otherwise.
+func DecodeContext(ctx context.Context, j EpicJWT, token string, claims gojwt.Claims) error {
+	if decoder, ok := j.(ContextDecoder); ok {
+		return decoder.DecodeContext(ctx, token, claims)
+	}
+	return j.Decode(token, claims)
+}
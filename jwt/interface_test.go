@@ -0,0 +1,57 @@
+package jwt
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestNewKeyStrength(t *testing.T) {
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 1024)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ecKey, err := ecdsa.GenerateKey(elliptic.P384(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name    string
+		cfg     Config
+		err     error
+		errText string
+	}{
+		{name: "short secret", cfg: Config{Algorithm: HS256, Secret: "short"}, err: ErrWeakKey},
+		{name: "secret below hash size", cfg: Config{Algorithm: HS512, Secret: strings.Repeat("s", 32)}, err: ErrWeakKey},
+		{name: "strong secret", cfg: Config{Algorithm: HS256, Secret: strings.Repeat("s", 32)}},
+		{name: "weak secret allowed", cfg: Config{Algorithm: HS256, Secret: "short", WeakKey: true}},
+		{name: "small rsa key", cfg: Config{Algorithm: RS256, PublicKey: &rsaKey.PublicKey}, err: ErrWeakKey},
+		{name: "small rsa key allowed", cfg: Config{Algorithm: RS256, PublicKey: &rsaKey.PublicKey, WeakKey: true}},
+		{name: "wrong curve", cfg: Config{Algorithm: ES256, ECPublicKey: &ecKey.PublicKey}, errText: "requires a 256-bit curve"},
+		{name: "wrong curve with weak key", cfg: Config{Algorithm: ES256, ECPublicKey: &ecKey.PublicKey, WeakKey: true}, errText: "requires a 256-bit curve"},
+		{name: "matching curve", cfg: Config{Algorithm: ES384, ECPublicKey: &ecKey.PublicKey}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := New(tt.cfg)
+			switch {
+			case tt.err != nil:
+				if !errors.Is(err, tt.err) {
+					t.Fatalf("err = %v, want %v", err, tt.err)
+				}
+			case tt.errText != "":
+				if err == nil || !strings.Contains(err.Error(), tt.errText) {
+					t.Fatalf("err = %v, want %q", err, tt.errText)
+				}
+			case err != nil:
+				t.Fatal(err)
+			}
+		})
+	}
+}
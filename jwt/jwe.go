@@ -0,0 +1,101 @@
+package jwt
+
+import (
+	"context"
+	"crypto/rsa"
+	"errors"
+	"fmt"
+
+	jose "github.com/go-jose/go-jose/v4"
+	gojwt "github.com/golang-jwt/jwt/v5"
+)
+
+// Encryption keys nested tokens: signed JWTs wrapped in an A256GCM encrypted
+// JWE, so claims cannot be read from browser storage or logs.
+type Encryption struct {
+	// Key is the 32 byte content key shared by every service ("dir").
+	Key []byte
+
+	// Without Key the content key is wrapped with RSA-OAEP-256. PublicKey
+	// encrypts, PrivateKey decrypts. PublicKey may be omitted when
+	// PrivateKey is set.
+	PublicKey  *rsa.PublicKey
+	PrivateKey *rsa.PrivateKey
+}
+
+type epicNested struct {
+	inner      EpicJWT
+	algorithm  jose.KeyAlgorithm
+	encryptKey any
+	decryptKey any
+}
+
+// NewNested signs tokens with inner, then encrypts them. Decode decrypts
+// first and hands the signed token to inner, so claim validation is
+// unchanged.
+func NewNested(inner EpicJWT, enc Encryption) (EpicJWT, error) {
+	if enc.Key != nil {
+		if len(enc.Key) != 32 {
+			return nil, fmt.Errorf("%w: A256GCM requires a key of 32 bytes", ErrWeakKey)
+		}
+		return &epicNested{inner: inner, algorithm: jose.DIRECT, encryptKey: enc.Key, decryptKey: enc.Key}, nil
+	}
+
+	if enc.PublicKey == nil && enc.PrivateKey != nil {
+		enc.PublicKey = &enc.PrivateKey.PublicKey
+	}
+	if enc.PublicKey == nil {
+		return nil, errors.New("requires encryption key")
+	}
+	if enc.PublicKey.N.BitLen() < MinRSAKeyBits {
+		return nil, fmt.Errorf("%w: RSA-OAEP-256 requires a key of at least %d bits", ErrWeakKey, MinRSAKeyBits)
+	}
+
+	nested := &epicNested{inner: inner, algorithm: jose.RSA_OAEP_256, encryptKey: enc.PublicKey}
+	if enc.PrivateKey != nil {
+		nested.decryptKey = enc.PrivateKey
+	}
+	return nested, nil
+}
+
+func (n *epicNested) Sign(claims gojwt.Claims) (string, error) {
+	signed, err := n.inner.Sign(claims)
+	if err != nil {
+		return "", err
+	}
+
+	encrypter, err := jose.NewEncrypter(jose.A256GCM, jose.Recipient{Algorithm: n.algorithm, Key: n.encryptKey},
+		(&jose.EncrypterOptions{}).WithContentType("JWT"))
+	if err != nil {
+		return "", err
+	}
+	encrypted, err := encrypter.Encrypt([]byte(signed))
+	if err != nil {
+		return "", err
+	}
+	return encrypted.CompactSerialize()
+}
+
+func (n *epicNested) Verify(tokenStr string) error {
+	return n.Decode(tokenStr, gojwt.MapClaims{})
+}
+
+func (n *epicNested) Decode(tokenStr string, claims gojwt.Claims) error {
+	return n.DecodeContext(context.Background(), tokenStr, claims)
+}
+
+func (n *epicNested) DecodeContext(ctx context.Context, tokenStr string, claims gojwt.Claims) error {
+	if n.decryptKey == nil {
+		return errors.New("requires private key to decrypt jwe")
+	}
+
+	encrypted, err := jose.ParseEncryptedCompact(tokenStr, []jose.KeyAlgorithm{n.algorithm}, []jose.ContentEncryption{jose.A256GCM})
+	if err != nil {
+		return &VerificationError{Reason: ErrTokenMalformed, Err: err}
+	}
+	signed, err := encrypted.Decrypt(n.decryptKey)
+	if err != nil {
+		return &VerificationError{Reason: ErrInvalidToken, Err: err}
+	}
+	return DecodeContext(ctx, n.inner, string(signed), claims)
+}
@@ -0,0 +1,359 @@
+package jwt
+
+import (
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"net/http"
+	"os"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	gojwt "github.com/golang-jwt/jwt/v5"
+	"golang.org/x/sync/singleflight"
+)
+
+// JWK is a single JSON Web Key (RFC 7517). Only public parameters are
+// carried, except for "oct" keys which hold the shared secret in K.
+type JWK struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid,omitempty"`
+	Use string `json:"use,omitempty"`
+	Alg string `json:"alg,omitempty"`
+
+	// RSA
+	N string `json:"n,omitempty"`
+	E string `json:"e,omitempty"`
+
+	// EC and OKP
+	Crv string `json:"crv,omitempty"`
+	X   string `json:"x,omitempty"`
+	Y   string `json:"y,omitempty"`
+
+	// oct
+	K string `json:"k,omitempty"`
+}
+
+// JWKS is a JSON Web Key Set document.
+type JWKS struct {
+	Keys []JWK `json:"keys"`
+}
+
+var b64 = base64.RawURLEncoding
+
+// NewJWK describes a verification key as a JWK. Supported keys are
+// *rsa.PublicKey, *ecdsa.PublicKey, ed25519.PublicKey and []byte (HMAC secret).
+func NewJWK(kid string, algorithm gojwt.SigningMethod, key any) (JWK, error) {
+	jwk := JWK{Kid: kid, Use: "sig"}
+	if algorithm != nil {
+		jwk.Alg = algorithm.Alg()
+	}
+
+	switch k := key.(type) {
+	case *rsa.PublicKey:
+		jwk.Kty = "RSA"
+		jwk.N = b64.EncodeToString(k.N.Bytes())
+		jwk.E = b64.EncodeToString(big.NewInt(int64(k.E)).Bytes())
+	case *ecdsa.PublicKey:
+		size := (k.Curve.Params().BitSize + 7) / 8
+		jwk.Kty = "EC"
+		jwk.Crv = k.Curve.Params().Name
+		jwk.X = b64.EncodeToString(k.X.FillBytes(make([]byte, size)))
+		jwk.Y = b64.EncodeToString(k.Y.FillBytes(make([]byte, size)))
+	case ed25519.PublicKey:
+		jwk.Kty = "OKP"
+		jwk.Crv = "Ed25519"
+		jwk.X = b64.EncodeToString(k)
+	case []byte:
+		jwk.Kty = "oct"
+		jwk.K = b64.EncodeToString(k)
+	default:
+		return JWK{}, fmt.Errorf("unsupported jwk key type %T", key)
+	}
+
+	return jwk, nil
+}
+
+// PublicKey returns the key in the form expected by the golang-jwt signing methods.
+func (k JWK) PublicKey() (any, error) {
+	switch k.Kty {
+	case "RSA":
+		n, err := b64.DecodeString(k.N)
+		if err != nil {
+			return nil, err
+		}
+		e, err := b64.DecodeString(k.E)
+		if err != nil {
+			return nil, err
+		}
+		return &rsa.PublicKey{
+			N: new(big.Int).SetBytes(n),
+			E: int(new(big.Int).SetBytes(e).Int64()),
+		}, nil
+	case "EC":
+		var curve elliptic.Curve
+		switch k.Crv {
+		case "P-256":
+			curve = elliptic.P256()
+		case "P-384":
+			curve = elliptic.P384()
+		case "P-521":
+			curve = elliptic.P521()
+		default:
+			return nil, fmt.Errorf("unsupported jwk curve %q", k.Crv)
+		}
+		x, err := b64.DecodeString(k.X)
+		if err != nil {
+			return nil, err
+		}
+		y, err := b64.DecodeString(k.Y)
+		if err != nil {
+			return nil, err
+		}
+		return &ecdsa.PublicKey{
+			Curve: curve,
+			X:     new(big.Int).SetBytes(x),
+			Y:     new(big.Int).SetBytes(y),
+		}, nil
+	case "OKP":
+		if k.Crv != "Ed25519" {
+			return nil, fmt.Errorf("unsupported jwk curve %q", k.Crv)
+		}
+		x, err := b64.DecodeString(k.X)
+		if err != nil {
+			return nil, err
+		}
+		if len(x) != ed25519.PublicKeySize {
+			return nil, errors.New("invalid ed25519 jwk")
+		}
+		return ed25519.PublicKey(x), nil
+	case "oct":
+		return b64.DecodeString(k.K)
+	default:
+		return nil, fmt.Errorf("unsupported jwk key type %q", k.Kty)
+	}
+}
+
+// Key looks up a key by its id.
+func (s *JWKS) Key(kid string) (JWK, bool) {
+	for _, k := range s.Keys {
+		if k.Kid == kid {
+			return k, true
+		}
+	}
+	return JWK{}, false
+}
+
+// Public returns a copy of the set without shared secrets, safe to publish.
+func (s *JWKS) Public() *JWKS {
+	public := &JWKS{Keys: []JWK{}}
+	for _, k := range s.Keys {
+		if k.Kty == "oct" {
+			continue
+		}
+		public.Keys = append(public.Keys, k)
+	}
+	return public
+}
+
+func ParseJWKS(data []byte) (*JWKS, error) {
+	var set JWKS
+	if err := json.Unmarshal(data, &set); err != nil {
+		return nil, fmt.Errorf("failed to parse jwks: %w", err)
+	}
+	return &set, nil
+}
+
+// KeyProvider supplies the key set used to verify tokens.
+type KeyProvider interface {
+	KeySet() (*JWKS, error)
+}
+
+type staticKeys struct {
+	set *JWKS
+}
+
+// NewStaticKeys serves an in-memory key set.
+func NewStaticKeys(set *JWKS) KeyProvider {
+	return &staticKeys{set: set}
+}
+
+func (s *staticKeys) KeySet() (*JWKS, error) {
+	return s.set, nil
+}
+
+// NewFileKeys reads a key set from a local JWKS file.
+func NewFileKeys(path string) (KeyProvider, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	set, err := ParseJWKS(data)
+	if err != nil {
+		return nil, err
+	}
+
+	return NewStaticKeys(set), nil
+}
+
+// Minimum delay between two forced refreshes triggered by unknown key ids,
+// and first delay before retrying a failed fetch.
+const remoteKeysMinRefresh = 30 * time.Second
+
+// Longest delay between retries while the jwks endpoint keeps failing.
+const remoteKeysMaxBackoff = 10 * time.Minute
+
+// Refresh interval of NewRemoteKeys when none is given.
+const remoteKeysDefaultRefresh = 15 * time.Minute
+
+type remoteKeys struct {
+	url     string
+	refresh time.Duration
+	client  *http.Client
+
+	// group runs one fetch at a time, the lock is never held during it.
+	group singleflight.Group
+	// refreshing is set while a background refresh of a stale set runs.
+	refreshing atomic.Bool
+
+	mu        sync.RWMutex
+	set       *JWKS
+	fetchedAt time.Time
+	failedAt  time.Time
+	failures  int
+	err       error // of the last failed fetch
+}
+
+// NewRemoteKeys fetches a key set from url and refetches it once it is older
+// than refresh. A stale set keeps being served while it is refetched or while
+// the endpoint fails, retries then back off up to 10 minutes apart. refresh
+// defaults to 15 minutes.
+func NewRemoteKeys(url string, refresh time.Duration) KeyProvider {
+	if refresh <= 0 {
+		refresh = remoteKeysDefaultRefresh
+	}
+	return &remoteKeys{
+		url:     url,
+		refresh: refresh,
+		client:  &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+func (r *remoteKeys) KeySet() (*JWKS, error) {
+	r.mu.RLock()
+	set, fetchedAt, backingOff, err := r.set, r.fetchedAt, r.backingOff(), r.err
+	r.mu.RUnlock()
+
+	switch {
+	case set != nil && time.Since(fetchedAt) < r.refresh:
+		return set, nil
+	case set != nil:
+		if !backingOff && r.refreshing.CompareAndSwap(false, true) {
+			go func() {
+				defer r.refreshing.Store(false)
+				r.fetch()
+			}()
+		}
+		return set, nil
+	case backingOff:
+		return nil, err
+	default:
+		return r.fetch()
+	}
+}
+
+// forceRefresh refetches the key set when a token references a key id that
+// is not known yet, e.g. right after the issuer rotated its keys.
+func (r *remoteKeys) forceRefresh() (*JWKS, error) {
+	r.mu.RLock()
+	set, fetchedAt, backingOff, err := r.set, r.fetchedAt, r.backingOff(), r.err
+	r.mu.RUnlock()
+
+	if set != nil && (backingOff || time.Since(fetchedAt) < remoteKeysMinRefresh) {
+		return set, nil
+	}
+	if set == nil && backingOff {
+		return nil, err
+	}
+	return r.fetch()
+}
+
+// backingOff reports whether the last fetch failed too recently to retry,
+// r.mu must be held.
+func (r *remoteKeys) backingOff() bool {
+	if r.failures == 0 {
+		return false
+	}
+	backoff := remoteKeysMinRefresh << min(r.failures-1, 5)
+	return time.Since(r.failedAt) < min(backoff, remoteKeysMaxBackoff)
+}
+
+// fetch downloads the key set, concurrent callers share one request. The
+// last known set is returned when it fails.
+func (r *remoteKeys) fetch() (*JWKS, error) {
+	v, err, _ := r.group.Do("jwks", func() (any, error) {
+		set, err := r.download()
+
+		r.mu.Lock()
+		defer r.mu.Unlock()
+		if err != nil {
+			r.failedAt = time.Now()
+			r.failures++
+			r.err = err
+			if r.set != nil {
+				return r.set, nil
+			}
+			return nil, err
+		}
+		r.set = set
+		r.fetchedAt = time.Now()
+		r.failures = 0
+		r.err = nil
+		return set, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return v.(*JWKS), nil
+}
+
+func (r *remoteKeys) download() (*JWKS, error) {
+	resp, err := r.client.Get(r.url)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected jwks status %d", resp.StatusCode)
+	}
+
+	var set JWKS
+	if err := json.NewDecoder(resp.Body).Decode(&set); err != nil {
+		return nil, fmt.Errorf("failed to parse jwks: %w", err)
+	}
+	return &set, nil
+}
+
+// JWKSHandler publishes the public keys of provider, typically mounted at
+// "/.well-known/jwks.json".
+func JWKSHandler(keys KeyProvider) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		set, err := keys.KeySet()
+		if err != nil {
+			return fiber.ErrServiceUnavailable
+		}
+
+		c.Set(fiber.HeaderCacheControl, "public, max-age=300")
+		return c.JSON(set.Public())
+	}
+}
@@ -0,0 +1,66 @@
+package jwt
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"runtime"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestRemoteKeysDefaultRefresh(t *testing.T) {
+	var hits atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hits.Add(1)
+		w.Write([]byte(`{"keys":[]}`))
+	}))
+	defer srv.Close()
+
+	keys := NewRemoteKeys(srv.URL, 0)
+	for i := 0; i < 3; i++ {
+		if _, err := keys.KeySet(); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if n := hits.Load(); n != 1 {
+		t.Fatalf("jwks fetched %d times, want 1", n)
+	}
+}
+
+// A stale set is served while one background refresh runs, further calls do
+// not start more.
+func TestRemoteKeysSingleRefresh(t *testing.T) {
+	var hits atomic.Int32
+	release := make(chan struct{})
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if hits.Add(1) > 1 {
+			<-release
+		}
+		w.Write([]byte(`{"keys":[]}`))
+	}))
+	defer srv.Close()
+
+	keys := NewRemoteKeys(srv.URL, time.Nanosecond).(*remoteKeys)
+	if _, err := keys.KeySet(); err != nil {
+		t.Fatal(err)
+	}
+
+	goroutines := runtime.NumGoroutine()
+	for i := 0; i < 50; i++ {
+		if set, err := keys.KeySet(); err != nil || set == nil {
+			t.Fatalf("stale set not served: %v", err)
+		}
+	}
+	if n := runtime.NumGoroutine() - goroutines; n > 10 {
+		t.Fatalf("%d goroutines started for one refresh", n)
+	}
+
+	close(release)
+	for keys.refreshing.Load() {
+		time.Sleep(time.Millisecond)
+	}
+	if n := hits.Load(); n != 2 {
+		t.Fatalf("jwks fetched %d times, want 2", n)
+	}
+}
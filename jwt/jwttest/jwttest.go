@@ -0,0 +1,154 @@
+// Package jwttest issues tokens for unit tests of services built on the jwt
+// package: valid ones, and each kind of token a verifier must reject.
+//
+//	issuer := jwttest.New(t)
+//	app.Use(jwt.Middleware[*pkgep.VerifiedToken](issuer.JWT))
+//
+//	req := issuer.Authorize(t, httptest.NewRequest("GET", "/me", nil), nil)
+//	resp, _ := app.Test(req)
+package jwttest
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/epicconsult/pkgep/jwt"
+	gojwt "github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+)
+
+// DefaultUserID and DefaultRole fill the "sub" of tokens built without one.
+const (
+	DefaultUserID = 1
+	DefaultRole   = "user"
+)
+
+// Subject returns a "sub" claim shaped like pkgep.SubClaims, which
+// pkgep.VerifiedToken decodes.
+//
+//	issuer.Valid(t, gojwt.MapClaims{"sub": jwttest.Subject(7, "admin")})
+func Subject(userID int, role string) map[string]any {
+	return map[string]any{"user_id": userID, "role": role}
+}
+
+// Issuer signs RS256 tokens with an ephemeral key. JWT verifies them and is
+// what the service under test should be configured with.
+type Issuer struct {
+	JWT        jwt.EpicJWT
+	PrivateKey *rsa.PrivateKey
+	PublicKey  *rsa.PublicKey
+
+	// Now is the reference time of built tokens, default time.Now.
+	Now func() time.Time
+}
+
+// New returns an Issuer with a fresh key, opts configure JWT as for
+// jwt.NewRSA, e.g. jwt.WithIssuer.
+func New(t testing.TB, opts ...jwt.Option) *Issuer {
+	t.Helper()
+
+	private, public := NewKeyPair(t)
+	return &Issuer{
+		JWT:        jwt.NewRSA(private, public, jwt.RS256, opts...),
+		PrivateKey: private,
+		PublicKey:  public,
+		Now:        time.Now,
+	}
+}
+
+// NewKeyPair generates an RSA key of jwt.MinRSAKeyBits.
+func NewKeyPair(t testing.TB) (*rsa.PrivateKey, *rsa.PublicKey) {
+	t.Helper()
+
+	private, err := rsa.GenerateKey(rand.Reader, jwt.MinRSAKeyBits)
+	if err != nil {
+		t.Fatalf("jwttest: failed to generate key: %v", err)
+	}
+	return private, &private.PublicKey
+}
+
+// Claims returns the claims of a valid token: sub, jti, iat and exp an hour
+// ahead, overridden by extra.
+func (i *Issuer) Claims(extra gojwt.MapClaims) gojwt.MapClaims {
+	now := i.Now()
+	claims := gojwt.MapClaims{
+		"sub": Subject(DefaultUserID, DefaultRole),
+		"jti": uuid.NewString(),
+		"iat": now.Unix(),
+		"exp": now.Add(time.Hour).Unix(),
+	}
+	for k, v := range extra {
+		claims[k] = v
+	}
+	return claims
+}
+
+// Valid signs a token JWT accepts. extra may be nil, or hold e.g.
+// {"sub": Subject(7, "admin")}.
+func (i *Issuer) Valid(t testing.TB, extra gojwt.MapClaims) string {
+	t.Helper()
+	return i.sign(t, i.JWT, i.Claims(extra))
+}
+
+// Expired signs a token that expired a minute ago.
+func (i *Issuer) Expired(t testing.TB, extra gojwt.MapClaims) string {
+	t.Helper()
+	now := i.Now()
+	claims := i.Claims(extra)
+	claims["iat"] = now.Add(-time.Hour).Unix()
+	claims["exp"] = now.Add(-time.Minute).Unix()
+	return i.sign(t, i.JWT, claims)
+}
+
+// NotYetValid signs a token whose "nbf" is an hour ahead.
+func (i *Issuer) NotYetValid(t testing.TB, extra gojwt.MapClaims) string {
+	t.Helper()
+	claims := i.Claims(extra)
+	claims["nbf"] = i.Now().Add(time.Hour).Unix()
+	return i.sign(t, i.JWT, claims)
+}
+
+// WrongSignature signs a token with another key than JWT's.
+func (i *Issuer) WrongSignature(t testing.TB, extra gojwt.MapClaims) string {
+	t.Helper()
+	other, otherPublic := NewKeyPair(t)
+	return i.sign(t, jwt.NewRSA(other, otherPublic, jwt.RS256), i.Claims(extra))
+}
+
+// WrongAlgorithm signs an HS256 token keyed with the PEM encoded public key,
+// the classic algorithm confusion attack against RS256 verifiers.
+func (i *Issuer) WrongAlgorithm(t testing.TB, extra gojwt.MapClaims) string {
+	t.Helper()
+	der, err := x509.MarshalPKIXPublicKey(i.PublicKey)
+	if err != nil {
+		t.Fatalf("jwttest: failed to encode public key: %v", err)
+	}
+	secret := pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der})
+	return i.sign(t, jwt.NewHMAC(string(secret), jwt.HS256), i.Claims(extra))
+}
+
+func (i *Issuer) sign(t testing.TB, j jwt.EpicJWT, claims gojwt.MapClaims) string {
+	t.Helper()
+	token, err := j.Sign(claims)
+	if err != nil {
+		t.Fatalf("jwttest: failed to sign token: %v", err)
+	}
+	return token
+}
+
+// Authorize sets a valid bearer token on req, ready for app.Test.
+func (i *Issuer) Authorize(t testing.TB, req *http.Request, extra gojwt.MapClaims) *http.Request {
+	t.Helper()
+	return SetBearer(req, i.Valid(t, extra))
+}
+
+// SetBearer sets token as the Authorization header of req.
+func SetBearer(req *http.Request, token string) *http.Request {
+	req.Header.Set("Authorization", "Bearer "+token)
+	return req
+}
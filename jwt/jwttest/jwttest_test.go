@@ -0,0 +1,83 @@
+package jwttest_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/epicconsult/pkgep"
+	"github.com/epicconsult/pkgep/jwt"
+	"github.com/epicconsult/pkgep/jwt/jwttest"
+	"github.com/gofiber/fiber/v2"
+	gojwt "github.com/golang-jwt/jwt/v5"
+)
+
+func TestVerifiedTokenMiddleware(t *testing.T) {
+	issuer := jwttest.New(t)
+	pkgep.NewHelpers(*pkgep.MockLogger("jwttest")) // ErrorResponse logs through it
+
+	app := fiber.New()
+	app.Use(jwt.Middleware[*pkgep.VerifiedToken](issuer.JWT, jwt.MiddlewareConfig{
+		ErrorHandler: pkgep.TokenErrorResponse,
+	}))
+	app.Get("/me", func(c *fiber.Ctx) error {
+		return c.JSON(c.Locals(jwt.DefaultContextKey).(*pkgep.VerifiedToken).Sub)
+	})
+
+	tests := []struct {
+		name       string
+		token      string
+		httpStatus int
+		appStatus  pkgep.AppResponseStatus
+	}{
+		{name: "valid", token: issuer.Valid(t, nil), httpStatus: http.StatusOK},
+		{name: "expired", token: issuer.Expired(t, nil), httpStatus: http.StatusUnauthorized, appStatus: pkgep.TokenExpired},
+		{name: "not yet valid", token: issuer.NotYetValid(t, nil), httpStatus: http.StatusBadRequest, appStatus: pkgep.InvalidToken},
+		{name: "wrong signature", token: issuer.WrongSignature(t, nil), httpStatus: http.StatusBadRequest, appStatus: pkgep.InvalidToken},
+		{name: "wrong algorithm", token: issuer.WrongAlgorithm(t, nil), httpStatus: http.StatusBadRequest, appStatus: pkgep.InvalidToken},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp, err := app.Test(jwttest.SetBearer(httptest.NewRequest("GET", "/me", nil), tt.token))
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer resp.Body.Close()
+			if resp.StatusCode != tt.httpStatus {
+				t.Fatalf("status = %d, want %d", resp.StatusCode, tt.httpStatus)
+			}
+
+			if tt.appStatus == 0 {
+				var sub pkgep.SubClaims
+				if err := json.NewDecoder(resp.Body).Decode(&sub); err != nil {
+					t.Fatal(err)
+				}
+				if sub.UserID != jwttest.DefaultUserID || sub.Role != jwttest.DefaultRole {
+					t.Fatalf("sub = %+v", sub)
+				}
+				return
+			}
+
+			var body pkgep.ApiResponse
+			if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+				t.Fatal(err)
+			}
+			if body.StatusCode != tt.appStatus {
+				t.Fatalf("status code = %d, want %d (%s)", body.StatusCode, tt.appStatus, body.Message)
+			}
+		})
+	}
+}
+
+func TestSubject(t *testing.T) {
+	issuer := jwttest.New(t)
+	var claims pkgep.VerifiedToken
+	if err := issuer.JWT.Decode(issuer.Valid(t, gojwt.MapClaims{"sub": jwttest.Subject(7, "admin")}), &claims); err != nil {
+		t.Fatal(err)
+	}
+	if claims.Sub.UserID != 7 || claims.Sub.Role != "admin" {
+		t.Fatalf("sub = %+v", claims.Sub)
+	}
+}
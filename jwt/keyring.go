@@ -0,0 +1,140 @@
+package jwt
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"errors"
+
+	gojwt "github.com/golang-jwt/jwt/v5"
+)
+
+// SigningKey is the active key used to sign new tokens. Its ID is stamped
+// into the "kid" header so verifiers can pick the matching key from a JWKS.
+type SigningKey struct {
+	ID        string
+	Algorithm gojwt.SigningMethod
+	Key       any // []byte, *rsa.PrivateKey, *ecdsa.PrivateKey, ed25519.PrivateKey or a crypto.Signer
+}
+
+type epicKeyring struct {
+	signing *SigningKey
+	keys    KeyProvider
+	opts    options
+}
+
+// NewKeyring signs with the active signing key and verifies against any key
+// of the provided set, which allows rotating keys without a hard cutover.
+// signing may be nil for verify-only services.
+func NewKeyring(signing *SigningKey, keys KeyProvider, opts ...Option) EpicJWT {
+	return &epicKeyring{signing: signing, keys: keys, opts: newOptions(opts)}
+}
+
+func (k *epicKeyring) Sign(claims gojwt.Claims) (string, error) {
+	if k.signing == nil || k.signing.Key == nil {
+		return "", errors.New("requires signing key to sign jwt")
+	}
+	token := gojwt.NewWithClaims(k.signing.Algorithm, claims)
+	token.Header["kid"] = k.signing.ID
+	if signer, ok := k.signing.Key.(crypto.Signer); ok && !isPrivateKey(signer) {
+		return signWith(token, signer)
+	}
+	signedToken, err := token.SignedString(k.signing.Key)
+	if err != nil {
+		return "", err
+	}
+	return signedToken, nil
+}
+
+// isPrivateKey reports whether golang-jwt signs with key itself, as opposed
+// to an external crypto.Signer.
+func isPrivateKey(key any) bool {
+	switch key.(type) {
+	case *rsa.PrivateKey, *ecdsa.PrivateKey, ed25519.PrivateKey:
+		return true
+	default:
+		return false
+	}
+}
+
+func (k *epicKeyring) Verify(tokenStr string) error {
+	return k.opts.parse(context.Background(), tokenStr, gojwt.MapClaims{}, k.keyFunc)
+}
+
+func (k *epicKeyring) Decode(tokenStr string, claims gojwt.Claims) error {
+	return k.DecodeContext(context.Background(), tokenStr, claims)
+}
+
+func (k *epicKeyring) DecodeContext(ctx context.Context, tokenStr string, claims gojwt.Claims) error {
+	return k.opts.parse(ctx, tokenStr, claims, k.keyFunc)
+}
+
+func (k *epicKeyring) keyFunc(token *gojwt.Token) (any, error) {
+	kid, _ := token.Header["kid"].(string)
+	if kid == "" {
+		return nil, ErrUnknownKeyID
+	}
+
+	jwk, err := k.lookup(kid)
+	if err != nil {
+		return nil, err
+	}
+	if jwk.Alg != "" && jwk.Alg != token.Method.Alg() {
+		return nil, unexpectedMethod(token)
+	}
+
+	key, err := jwk.PublicKey()
+	if err != nil {
+		return nil, err
+	}
+	if !keyMatchesMethod(key, token.Method) {
+		return nil, unexpectedMethod(token)
+	}
+	return key, nil
+}
+
+func (k *epicKeyring) lookup(kid string) (JWK, error) {
+	set, err := k.keys.KeySet()
+	if err != nil {
+		return JWK{}, err
+	}
+	if jwk, ok := set.Key(kid); ok {
+		return jwk, nil
+	}
+
+	// The issuer may have rotated since the set was last fetched.
+	if remote, ok := k.keys.(*remoteKeys); ok {
+		set, err = remote.forceRefresh()
+		if err != nil {
+			return JWK{}, err
+		}
+		if jwk, ok := set.Key(kid); ok {
+			return jwk, nil
+		}
+	}
+	return JWK{}, ErrUnknownKeyID
+}
+
+// keyMatchesMethod guards against algorithm confusion, e.g. an RSA public key
+// being used as an HMAC secret.
+func keyMatchesMethod(key any, method gojwt.SigningMethod) bool {
+	switch key.(type) {
+	case *rsa.PublicKey:
+		switch method.(type) {
+		case *gojwt.SigningMethodRSA, *gojwt.SigningMethodRSAPSS:
+			return true
+		}
+	case *ecdsa.PublicKey:
+		_, ok := method.(*gojwt.SigningMethodECDSA)
+		return ok
+	case ed25519.PublicKey:
+		_, ok := method.(*gojwt.SigningMethodEd25519)
+		return ok
+	case []byte:
+		_, ok := method.(*gojwt.SigningMethodHMAC)
+		return ok
+	}
+	return false
+}
@@ -0,0 +1,206 @@
+package jwt
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// KeySource yields PEM encoded key material, wherever it is stored.
+type KeySource interface {
+	Load() ([]byte, error)
+}
+
+type fileKey string
+
+// FileKey reads key material from a file, e.g. a mounted secret.
+func FileKey(path string) KeySource {
+	return fileKey(path)
+}
+
+func (f fileKey) Load() ([]byte, error) {
+	return os.ReadFile(string(f))
+}
+
+type envKey string
+
+// EnvKey reads key material from an environment variable holding either a
+// PEM document or its base64 encoding.
+func EnvKey(name string) KeySource {
+	return envKey(name)
+}
+
+func (e envKey) Load() ([]byte, error) {
+	value, ok := os.LookupEnv(string(e))
+	if !ok || strings.TrimSpace(value) == "" {
+		return nil, fmt.Errorf("environment variable %s is not set", string(e))
+	}
+	return decodeKeyValue(value)
+}
+
+type bytesKey []byte
+
+// PEMKey serves key material held in memory.
+func PEMKey(data []byte) KeySource {
+	return bytesKey(data)
+}
+
+// PEMString serves key material held in a string, PEM or base64 encoded.
+func PEMString(value string) KeySource {
+	data, err := decodeKeyValue(value)
+	if err != nil {
+		return errKey{err}
+	}
+	return bytesKey(data)
+}
+
+func (b bytesKey) Load() ([]byte, error) {
+	return b, nil
+}
+
+type errKey struct{ err error }
+
+func (e errKey) Load() ([]byte, error) {
+	return nil, e.err
+}
+
+// decodeKeyValue accepts a PEM document, possibly with escaped newlines as
+// often found in env files, or its base64 encoding.
+func decodeKeyValue(value string) ([]byte, error) {
+	value = strings.TrimSpace(value)
+	if strings.HasPrefix(value, "-----BEGIN") {
+		return []byte(strings.ReplaceAll(value, `\n`, "\n")), nil
+	}
+
+	for _, enc := range []*base64.Encoding{base64.StdEncoding, base64.RawStdEncoding, base64.URLEncoding, base64.RawURLEncoding} {
+		if data, err := enc.DecodeString(value); err == nil {
+			return data, nil
+		}
+	}
+	return nil, errors.New("key is neither PEM nor base64 encoded PEM")
+}
+
+// LoadPublicKeyFrom reads a public key from src, see ParsePublicKeyPEM.
+func LoadPublicKeyFrom(src KeySource) (crypto.PublicKey, error) {
+	data, err := src.Load()
+	if err != nil {
+		return nil, err
+	}
+	return ParsePublicKeyPEM(data)
+}
+
+// LoadPrivateKeyFrom reads a private key from src, see ParsePrivateKeyPEM.
+func LoadPrivateKeyFrom(src KeySource, password []byte) (crypto.PrivateKey, error) {
+	data, err := src.Load()
+	if err != nil {
+		return nil, err
+	}
+	return ParsePrivateKeyPEM(data, password)
+}
+
+// ParsePublicKeyPEM parses a PKIX or PKCS #1 public key, or an X.509
+// certificate, into an *rsa.PublicKey, *ecdsa.PublicKey or ed25519.PublicKey.
+func ParsePublicKeyPEM(data []byte) (crypto.PublicKey, error) {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, errors.New("failed to parse PEM block containing the public key")
+	}
+
+	switch block.Type {
+	case "CERTIFICATE":
+		cert, err := x509.ParseCertificate(block.Bytes)
+		if err != nil {
+			return nil, err
+		}
+		return cert.PublicKey, nil
+	case "RSA PUBLIC KEY":
+		return x509.ParsePKCS1PublicKey(block.Bytes)
+	default:
+		return x509.ParsePKIXPublicKey(block.Bytes)
+	}
+}
+
+// ParsePrivateKeyPEM parses a PKCS #1, SEC 1 or PKCS #8 private key into an
+// *rsa.PrivateKey, *ecdsa.PrivateKey or ed25519.PrivateKey. Encrypted keys,
+// either PKCS #8 "ENCRYPTED PRIVATE KEY" (PBES2) or legacy OpenSSL
+// "Proc-Type: 4,ENCRYPTED", are decrypted with password.
+func ParsePrivateKeyPEM(data []byte, password []byte) (crypto.PrivateKey, error) {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, errors.New("failed to parse PEM block containing the private key")
+	}
+
+	der := block.Bytes
+	// Legacy PEM encryption is deprecated but still produced by OpenSSL 1.x.
+	if x509.IsEncryptedPEMBlock(block) {
+		if len(password) == 0 {
+			return nil, errors.New("requires password to decrypt private key")
+		}
+		var err error
+		if der, err = x509.DecryptPEMBlock(block, password); err != nil {
+			return nil, err
+		}
+	}
+
+	switch block.Type {
+	case "RSA PRIVATE KEY":
+		return x509.ParsePKCS1PrivateKey(der)
+	case "EC PRIVATE KEY":
+		return x509.ParseECPrivateKey(der)
+	case "ENCRYPTED PRIVATE KEY":
+		if len(password) == 0 {
+			return nil, errors.New("requires password to decrypt private key")
+		}
+		decrypted, err := decryptPKCS8(der, password)
+		if err != nil {
+			return nil, err
+		}
+		return x509.ParsePKCS8PrivateKey(decrypted)
+	default:
+		return x509.ParsePKCS8PrivateKey(der)
+	}
+}
+
+// SetKeys fills the key fields of cfg matching the concrete key types. public
+// may be nil, it is then derived from private.
+func (cfg *Config) SetKeys(private crypto.PrivateKey, public crypto.PublicKey) error {
+	if public == nil {
+		signer, ok := private.(crypto.Signer)
+		if !ok {
+			return fmt.Errorf("unsupported private key type %T", private)
+		}
+		public = signer.Public()
+	}
+
+	switch key := private.(type) {
+	case nil:
+	case *rsa.PrivateKey:
+		cfg.PrivateKey = key
+	case *ecdsa.PrivateKey:
+		cfg.ECPrivateKey = key
+	case ed25519.PrivateKey:
+		cfg.EdPrivateKey = key
+	default:
+		return fmt.Errorf("unsupported private key type %T", private)
+	}
+
+	switch key := public.(type) {
+	case *rsa.PublicKey:
+		cfg.PublicKey = key
+	case *ecdsa.PublicKey:
+		cfg.ECPublicKey = key
+	case ed25519.PublicKey:
+		cfg.EdPublicKey = key
+	default:
+		return fmt.Errorf("unsupported public key type %T", public)
+	}
+	return nil
+}
@@ -0,0 +1,155 @@
+package jwt
+
+import (
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"encoding/base64"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func testdataFile(t *testing.T, name string) []byte {
+	t.Helper()
+	data, err := os.ReadFile(filepath.Join("testdata", name))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return data
+}
+
+func TestKeySources(t *testing.T) {
+	pemData := testdataFile(t, "ec_pub.pem")
+	escaped := strings.ReplaceAll(strings.TrimSpace(string(pemData)), "\n", `\n`)
+
+	t.Setenv("JWTTEST_PEM", string(pemData))
+	t.Setenv("JWTTEST_ESCAPED", escaped)
+	t.Setenv("JWTTEST_BASE64", base64.StdEncoding.EncodeToString(pemData))
+	t.Setenv("JWTTEST_RAW_URL", base64.RawURLEncoding.EncodeToString(pemData))
+	t.Setenv("JWTTEST_BLANK", " ")
+	t.Setenv("JWTTEST_GARBAGE", "not a key!")
+
+	tests := []struct {
+		name    string
+		src     KeySource
+		wantErr bool
+	}{
+		{name: "file", src: FileKey(filepath.Join("testdata", "ec_pub.pem"))},
+		{name: "missing file", src: FileKey(filepath.Join("testdata", "missing.pem")), wantErr: true},
+		{name: "env pem", src: EnvKey("JWTTEST_PEM")},
+		{name: "env escaped newlines", src: EnvKey("JWTTEST_ESCAPED")},
+		{name: "env base64", src: EnvKey("JWTTEST_BASE64")},
+		{name: "env raw url base64", src: EnvKey("JWTTEST_RAW_URL")},
+		{name: "env unset", src: EnvKey("JWTTEST_UNSET"), wantErr: true},
+		{name: "env blank", src: EnvKey("JWTTEST_BLANK"), wantErr: true},
+		{name: "env garbage", src: EnvKey("JWTTEST_GARBAGE"), wantErr: true},
+		{name: "pem bytes", src: PEMKey(pemData)},
+		{name: "pem string", src: PEMString(escaped)},
+		{name: "pem string garbage", src: PEMString("not a key!"), wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			key, err := LoadPublicKeyFrom(tt.src)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatal("expected an error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if _, ok := key.(*ecdsa.PublicKey); !ok {
+				t.Fatalf("key is %T, want *ecdsa.PublicKey", key)
+			}
+		})
+	}
+}
+
+func TestParsePublicKeyPEM(t *testing.T) {
+	tests := []struct {
+		file string
+		want any
+	}{
+		{file: "ec_pub.pem", want: &ecdsa.PublicKey{}},
+		{file: "ec_cert.pem", want: &ecdsa.PublicKey{}},
+		{file: "rsa_pub_pkcs1.pem", want: &rsa.PublicKey{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.file, func(t *testing.T) {
+			key, err := ParsePublicKeyPEM(testdataFile(t, tt.file))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if reflect.TypeOf(key) != reflect.TypeOf(tt.want) {
+				t.Fatalf("key is %T, want %T", key, tt.want)
+			}
+		})
+	}
+
+	if _, err := ParsePublicKeyPEM([]byte("not a key")); err == nil {
+		t.Fatal("expected an error without PEM block")
+	}
+}
+
+func TestParsePrivateKeyPEM(t *testing.T) {
+	tests := []struct {
+		file     string
+		password string
+		want     any
+		wantErr  bool
+	}{
+		{file: "ec.pem", want: &ecdsa.PrivateKey{}},
+		{file: "ec_sec1.pem", want: &ecdsa.PrivateKey{}},
+		{file: "rsa.pem", want: &rsa.PrivateKey{}},
+		{file: "ed25519.pem", want: ed25519.PrivateKey{}},
+		{file: "ec_aes256_sha256.pem", password: "secret", want: &ecdsa.PrivateKey{}},
+		{file: "ec_aes256_sha256.pem", wantErr: true},
+		{file: "ec_aes256_sha256.pem", password: "wrong", wantErr: true},
+		{file: "ec_legacy.pem", password: "secret", want: &ecdsa.PrivateKey{}},
+		{file: "ec_legacy.pem", wantErr: true},
+		{file: "ec_legacy.pem", password: "wrong", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.file+"/"+tt.password, func(t *testing.T) {
+			key, err := ParsePrivateKeyPEM(testdataFile(t, tt.file), []byte(tt.password))
+			if tt.wantErr {
+				if err == nil {
+					t.Fatal("expected an error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if reflect.TypeOf(key) != reflect.TypeOf(tt.want) {
+				t.Fatalf("key is %T, want %T", key, tt.want)
+			}
+		})
+	}
+}
+
+func TestSetKeys(t *testing.T) {
+	private, err := LoadPrivateKeyFrom(FileKey(filepath.Join("testdata", "ec.pem")), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var cfg Config
+	if err := cfg.SetKeys(private, nil); err != nil {
+		t.Fatal(err)
+	}
+	if cfg.ECPrivateKey == nil || cfg.ECPublicKey == nil || !cfg.ECPrivateKey.PublicKey.Equal(cfg.ECPublicKey) {
+		t.Fatal("expected the EC key pair to be set")
+	}
+
+	if err := cfg.SetKeys("not a key", nil); err == nil {
+		t.Fatal("expected an error for an unsupported key")
+	}
+}
@@ -0,0 +1,165 @@
+package jwt
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+
+	"github.com/epicconsult/pkgep/logger"
+	"github.com/gofiber/fiber/v2"
+	gojwt "github.com/golang-jwt/jwt/v5"
+)
+
+// DefaultContextKey is the c.Locals key holding verified claims.
+const DefaultContextKey = "jwt"
+
+var ErrMissingToken = errors.New("token is missing")
+
+type MiddlewareConfig struct {
+	// TokenLookup lists "<source>:<name>" pairs separated by commas, tried in
+	// order. Sources are header, cookie and query.
+	// Default "header:Authorization".
+	TokenLookup string
+
+	// AuthScheme is stripped from header values. Default "Bearer".
+	AuthScheme string
+
+	// ContextKey is the c.Locals key claims are stored under.
+	// Default DefaultContextKey.
+	ContextKey string
+
+	// ErrorHandler answers requests with a missing or rejected token, err is
+	// ErrMissingToken or the error returned by EpicJWT.Decode.
+	// Default responds 401 Unauthorized.
+	ErrorHandler func(c *fiber.Ctx, err error) error
+
+	// Binding, when set, rejects tokens presented from another device with
+	// ErrTokenBindingMismatch and reports them through logger.Logger.
+	Binding *Binding
+}
+
+func (cfg MiddlewareConfig) withDefaults() MiddlewareConfig {
+	if cfg.TokenLookup == "" {
+		cfg.TokenLookup = "header:" + fiber.HeaderAuthorization
+	}
+	if cfg.AuthScheme == "" {
+		cfg.AuthScheme = "Bearer"
+	}
+	if cfg.ContextKey == "" {
+		cfg.ContextKey = DefaultContextKey
+	}
+	if cfg.ErrorHandler == nil {
+		cfg.ErrorHandler = func(c *fiber.Ctx, err error) error {
+			return fiber.ErrUnauthorized
+		}
+	}
+	return cfg
+}
+
+// Middleware verifies the request token with j and decodes it into a new T.
+// Claims are stored in c.Locals under the context key and added to the user
+// context with logger.With so EpicLogger picks them up. T must be a pointer or
+// map type.
+//
+//	app.Use(jwt.Middleware[*MyClaims](epicJwt))
+func Middleware[T gojwt.Claims](j EpicJWT, config ...MiddlewareConfig) fiber.Handler {
+	var cfg MiddlewareConfig
+	if len(config) > 0 {
+		cfg = config[0]
+	}
+	cfg = cfg.withDefaults()
+
+	return func(c *fiber.Ctx) error {
+		tokenStr := extractToken(c, cfg)
+		if tokenStr == "" {
+			return cfg.ErrorHandler(c, ErrMissingToken)
+		}
+
+		claims := newClaims[T]()
+		if err := DecodeContext(c.UserContext(), j, tokenStr, claims); err != nil {
+			return cfg.ErrorHandler(c, err)
+		}
+
+		c.SetUserContext(logger.With(c.UserContext(), claims))
+
+		if cfg.Binding != nil {
+			if err := cfg.Binding.check(c, claims); err != nil {
+				reportMismatch(c, err)
+				return cfg.ErrorHandler(c, err)
+			}
+		}
+
+		c.Locals(cfg.ContextKey, claims)
+		return c.Next()
+	}
+}
+
+func reportMismatch(c *fiber.Ctx, err error) {
+	if logger.Logger == nil {
+		return
+	}
+	fields := map[string]any{"path": c.Path(), "ip": c.IP(), "user_agent": c.Get(fiber.HeaderUserAgent)}
+	var verr *VerificationError
+	if errors.As(err, &verr) {
+		fields["claim"] = verr.Claim
+		fields["token_id"] = verr.TokenID
+	}
+	logger.Logger.Warn(c.UserContext(), err.Error(), fields)
+}
+
+// GetClaims returns the claims stored by Middleware, key defaults to
+// DefaultContextKey.
+func GetClaims[T gojwt.Claims](c *fiber.Ctx, key ...string) (T, bool) {
+	contextKey := DefaultContextKey
+	if len(key) > 0 {
+		contextKey = key[0]
+	}
+	claims, ok := c.Locals(contextKey).(T)
+	return claims, ok
+}
+
+func extractToken(c *fiber.Ctx, cfg MiddlewareConfig) string {
+	for _, lookup := range strings.Split(cfg.TokenLookup, ",") {
+		source, name, found := strings.Cut(strings.TrimSpace(lookup), ":")
+		if !found {
+			continue
+		}
+
+		var token string
+		switch source {
+		case "header":
+			token = c.Get(name)
+			if len(token) > len(cfg.AuthScheme) && strings.EqualFold(token[:len(cfg.AuthScheme)], cfg.AuthScheme) {
+				token = token[len(cfg.AuthScheme):]
+			}
+		case "cookie":
+			token = c.Cookies(name)
+		case "query":
+			token = c.Query(name)
+		}
+
+		if token = strings.TrimSpace(token); token != "" {
+			return token
+		}
+	}
+	return ""
+}
+
+// newClaims allocates the value behind T, e.g. a *VerifiedToken or a
+// non-nil MapClaims, so it can be decoded into.
+func newClaims[T gojwt.Claims]() T {
+	var zero T
+	typ := reflect.TypeOf(zero)
+	if typ == nil {
+		return zero
+	}
+
+	switch typ.Kind() {
+	case reflect.Pointer:
+		return reflect.New(typ.Elem()).Interface().(T)
+	case reflect.Map:
+		return reflect.MakeMap(typ).Interface().(T)
+	default:
+		return zero
+	}
+}
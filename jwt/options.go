@@ -0,0 +1,86 @@
+package jwt
+
+import (
+	"time"
+)
+
+// Registered claims that can be made mandatory with WithRequiredClaims.
+const (
+	ClaimExpiration = "exp"
+	ClaimIssuedAt   = "iat"
+	ClaimSubject    = "sub"
+)
+
+// ** Support configuration via "functional options pattern"
+type Option func(*options)
+
+type options struct {
+	issuer   string
+	audience []string
+	leeway   time.Duration
+	required []string
+	now      func() time.Time
+
+	revocation RevocationStore
+}
+
+func newOptions(opts []Option) options {
+	var o options
+	for _, opt := range opts {
+		opt(&o)
+	}
+	return o
+}
+
+// WithIssuer rejects tokens whose "iss" claim is not iss.
+func WithIssuer(iss string) Option {
+	return func(o *options) {
+		o.issuer = iss
+	}
+}
+
+// WithAudience rejects tokens whose "aud" claim contains none of aud.
+func WithAudience(aud ...string) Option {
+	return func(o *options) {
+		o.audience = aud
+	}
+}
+
+// WithLeeway tolerates clock skew when validating "exp", "nbf" and "iat".
+func WithLeeway(leeway time.Duration) Option {
+	return func(o *options) {
+		o.leeway = leeway
+	}
+}
+
+// WithRequiredClaims rejects tokens missing any of ClaimExpiration,
+// ClaimIssuedAt or ClaimSubject.
+func WithRequiredClaims(claims ...string) Option {
+	return func(o *options) {
+		o.required = claims
+	}
+}
+
+// WithClock replaces time.Now when validating time based claims.
+func WithClock(now func() time.Time) Option {
+	return func(o *options) {
+		o.now = now
+	}
+}
+
+// options translates the validation settings of cfg into Options.
+func (cfg Config) options() []Option {
+	opts := []Option{
+		WithIssuer(cfg.Issuer),
+		WithAudience(cfg.Audience...),
+		WithLeeway(cfg.Leeway),
+		WithRequiredClaims(cfg.RequiredClaims...),
+	}
+	if cfg.Now != nil {
+		opts = append(opts, WithClock(cfg.Now))
+	}
+	if cfg.Revocation != nil {
+		opts = append(opts, WithRevocation(cfg.Revocation))
+	}
+	return opts
+}
@@ -0,0 +1,149 @@
+package jwt
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+
+	gojwt "github.com/golang-jwt/jwt/v5"
+)
+
+// parse verifies tokenStr with keyFunc, decodes the payload into claims,
+// validates the claims against o and reports rejections as a
+// *VerificationError.
+func (o options) parse(ctx context.Context, tokenStr string, claims gojwt.Claims, keyFunc gojwt.Keyfunc) error {
+	token, err := gojwt.ParseWithClaims(tokenStr, claims, keyFunc, o.parserOptions()...)
+	var raw rawClaims
+	if token != nil {
+		raw = rawClaimsOf(token)
+	}
+	if err != nil {
+		return withTokenID(mapError(err), raw.ID)
+	}
+
+	if !token.Valid {
+		return &VerificationError{Reason: ErrInvalidToken, TokenID: raw.ID}
+	}
+	return withTokenID(o.check(ctx, claims, raw), raw.ID)
+}
+
+// rawClaims are the claims check needs that claims types do not expose.
+type rawClaims struct {
+	ID   string `json:"jti"`
+	Type string `json:"type"`
+}
+
+// check applies the validations shared by every token format once the
+// token is known to be authentic.
+func (o options) check(ctx context.Context, claims gojwt.Claims, raw rawClaims) error {
+	if err := o.validate(claims); err != nil {
+		return err
+	}
+	// Refresh tokens are only good for TokenPair, never as access tokens.
+	if _, ok := claims.(*refreshClaims); raw.Type == refreshTokenType && !ok {
+		return reject(ErrInvalidToken, "type")
+	}
+	return o.checkRevoked(ctx, claims, raw.ID)
+}
+
+func (o options) parserOptions() []gojwt.ParserOption {
+	parserOpts := []gojwt.ParserOption{gojwt.WithLeeway(o.leeway)}
+	if o.now != nil {
+		parserOpts = append(parserOpts, gojwt.WithTimeFunc(o.now))
+	}
+	if o.issuer != "" {
+		parserOpts = append(parserOpts, gojwt.WithIssuer(o.issuer))
+	}
+	if slices.Contains(o.required, ClaimIssuedAt) {
+		parserOpts = append(parserOpts, gojwt.WithIssuedAt())
+	}
+	return parserOpts
+}
+
+// validate applies the checks golang-jwt has no parser option for: any-of
+// audiences and required claims.
+func (o options) validate(claims gojwt.Claims) error {
+	if len(o.audience) > 0 {
+		aud, err := claims.GetAudience()
+		if err != nil || !slices.ContainsFunc(aud, func(a string) bool { return slices.Contains(o.audience, a) }) {
+			return reject(ErrTokenInvalidAudience, "aud")
+		}
+	}
+
+	for _, claim := range o.required {
+		var missing bool
+		switch claim {
+		case ClaimExpiration:
+			exp, err := claims.GetExpirationTime()
+			missing = err != nil || exp == nil
+		case ClaimIssuedAt:
+			iat, err := claims.GetIssuedAt()
+			missing = err != nil || iat == nil
+		case ClaimSubject:
+			sub, err := claims.GetSubject()
+			missing = err != nil || sub == ""
+		}
+		if missing {
+			return reject(ErrTokenRequiredClaimMissing, claim)
+		}
+	}
+	return nil
+}
+
+// checkRevoked consults the revocation store, if any, by token id and by
+// subject. Subject revocations are kept in whole seconds like "iat", tokens
+// issued in the second of the revocation stay valid. Tokens without "iat"
+// cannot prove they were issued after a subject revocation and are rejected.
+func (o options) checkRevoked(ctx context.Context, claims gojwt.Claims, jti string) error {
+	if o.revocation == nil {
+		return nil
+	}
+
+	if jti != "" {
+		revoked, err := o.revocation.IsRevoked(ctx, jti)
+		if err != nil {
+			return fmt.Errorf("failed to check token revocation: %w", err)
+		}
+		if revoked {
+			return reject(ErrTokenRevoked, "jti")
+		}
+	}
+
+	sub, _ := claims.GetSubject()
+	if sub == "" {
+		return nil
+	}
+	revokedAt, ok, err := o.revocation.SubjectRevokedAt(ctx, sub)
+	if err != nil {
+		return fmt.Errorf("failed to check token revocation: %w", err)
+	}
+	if !ok {
+		return nil
+	}
+	iat, _ := claims.GetIssuedAt()
+	if iat == nil || iat.Before(revokedAt) {
+		return reject(ErrTokenRevoked, ClaimSubject)
+	}
+	return nil
+}
+
+// rawClaimsOf reads rawClaims from the payload of token.
+func rawClaimsOf(token *gojwt.Token) rawClaims {
+	var raw rawClaims
+	parts := strings.Split(token.Raw, ".")
+	if len(parts) != 3 {
+		return raw
+	}
+	payload, err := gojwt.NewParser().DecodeSegment(parts[1])
+	if err != nil {
+		return raw
+	}
+	json.Unmarshal(payload, &raw)
+	return raw
+}
+
+func unexpectedMethod(token *gojwt.Token) error {
+	return fmt.Errorf("%w: %v", ErrUnexpectedSigningMethod, token.Header["alg"])
+}
@@ -0,0 +1,246 @@
+package jwt
+
+import (
+	"bytes"
+	"context"
+	"crypto/ed25519"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"aidanwoods.dev/go-paseto"
+	gojwt "github.com/golang-jwt/jwt/v5"
+)
+
+// PasetoMethod selects a PASETO v4 implementation through Config.Algorithm.
+// It is not a JWT signing method, gojwt refuses to sign or verify with it.
+type PasetoMethod struct {
+	purpose string
+}
+
+var (
+	// PasetoV4Public signs tokens with Config.EdPrivateKey and verifies them
+	// with Config.EdPublicKey.
+	PasetoV4Public = &PasetoMethod{purpose: "public"}
+
+	// PasetoV4Local encrypts tokens with Config.Secret, which must be
+	// exactly 32 bytes.
+	PasetoV4Local = &PasetoMethod{purpose: "local"}
+)
+
+func (m *PasetoMethod) Alg() string {
+	return "v4." + m.purpose
+}
+
+func (m *PasetoMethod) header() string {
+	return m.Alg() + "."
+}
+
+func (m *PasetoMethod) Verify(string, []byte, any) error {
+	return gojwt.ErrSignatureInvalid
+}
+
+func (m *PasetoMethod) Sign(string, any) ([]byte, error) {
+	return nil, errors.New("paseto tokens cannot be signed as jwt")
+}
+
+type epicPasetoPublic struct {
+	privateKey ed25519.PrivateKey
+	publicKey  ed25519.PublicKey
+	opts       options
+}
+
+// NewPasetoPublic issues v4.public tokens. Claims are the same as for JWT,
+// time based claims are converted to and from RFC 3339 as PASETO requires.
+func NewPasetoPublic(private ed25519.PrivateKey, public ed25519.PublicKey, opts ...Option) EpicJWT {
+	return &epicPasetoPublic{
+		privateKey: private,
+		publicKey:  public,
+		opts:       newOptions(opts),
+	}
+}
+
+func (p *epicPasetoPublic) Sign(claims gojwt.Claims) (string, error) {
+	if p.privateKey == nil {
+		return "", errors.New("requires private key to sign paseto")
+	}
+	key, err := paseto.NewV4AsymmetricSecretKeyFromEd25519(p.privateKey)
+	if err != nil {
+		return "", err
+	}
+	token, err := pasetoToken(claims)
+	if err != nil {
+		return "", err
+	}
+	return token.V4Sign(key, nil), nil
+}
+
+func (p *epicPasetoPublic) Verify(tokenStr string) error {
+	return p.Decode(tokenStr, gojwt.MapClaims{})
+}
+
+func (p *epicPasetoPublic) Decode(tokenStr string, claims gojwt.Claims) error {
+	return p.DecodeContext(context.Background(), tokenStr, claims)
+}
+
+func (p *epicPasetoPublic) DecodeContext(ctx context.Context, tokenStr string, claims gojwt.Claims) error {
+	if err := checkHeader(tokenStr, PasetoV4Public); err != nil {
+		return err
+	}
+	key, err := paseto.NewV4AsymmetricPublicKeyFromEd25519(p.publicKey)
+	if err != nil {
+		return err
+	}
+	token, err := paseto.MakeParser(nil).ParseV4Public(key, tokenStr, nil)
+	if err != nil {
+		return &VerificationError{Reason: ErrTokenSignatureInvalid, Err: err}
+	}
+	return p.opts.decodePaseto(ctx, token, claims)
+}
+
+type epicPasetoLocal struct {
+	key  paseto.V4SymmetricKey
+	opts options
+}
+
+// NewPasetoLocal issues encrypted v4.local tokens, key must be 32 bytes.
+func NewPasetoLocal(key []byte, opts ...Option) (EpicJWT, error) {
+	symmetric, err := paseto.V4SymmetricKeyFromBytes(key)
+	if err != nil {
+		return nil, fmt.Errorf("%w: v4.local requires a key of 32 bytes", ErrWeakKey)
+	}
+	return &epicPasetoLocal{key: symmetric, opts: newOptions(opts)}, nil
+}
+
+func (p *epicPasetoLocal) Sign(claims gojwt.Claims) (string, error) {
+	token, err := pasetoToken(claims)
+	if err != nil {
+		return "", err
+	}
+	return token.V4Encrypt(p.key, nil), nil
+}
+
+func (p *epicPasetoLocal) Verify(tokenStr string) error {
+	return p.Decode(tokenStr, gojwt.MapClaims{})
+}
+
+func (p *epicPasetoLocal) Decode(tokenStr string, claims gojwt.Claims) error {
+	return p.DecodeContext(context.Background(), tokenStr, claims)
+}
+
+func (p *epicPasetoLocal) DecodeContext(ctx context.Context, tokenStr string, claims gojwt.Claims) error {
+	if err := checkHeader(tokenStr, PasetoV4Local); err != nil {
+		return err
+	}
+	token, err := paseto.MakeParser(nil).ParseV4Local(p.key, tokenStr, nil)
+	if err != nil {
+		return &VerificationError{Reason: ErrInvalidToken, Err: err}
+	}
+	return p.opts.decodePaseto(ctx, token, claims)
+}
+
+// checkHeader rejects JWTs and PASETO tokens of another version or purpose
+// before any key is used.
+func checkHeader(tokenStr string, method *PasetoMethod) error {
+	if strings.HasPrefix(tokenStr, method.header()) {
+		return nil
+	}
+	parts := strings.SplitN(tokenStr, ".", 3)
+	if len(parts) == 3 && strings.HasPrefix(parts[0], "v") && (parts[1] == "public" || parts[1] == "local") {
+		return &VerificationError{Reason: ErrUnexpectedSigningMethod, Err: fmt.Errorf("%s.%s token", parts[0], parts[1])}
+	}
+	return reject(ErrTokenMalformed, "")
+}
+
+// decodePaseto applies the same claim validation as parse to an
+// authenticated PASETO token.
+func (o options) decodePaseto(ctx context.Context, token *paseto.Token, claims gojwt.Claims) error {
+	data, err := fromPasetoTimes(token.ClaimsJSON())
+	if err != nil {
+		return &VerificationError{Reason: ErrTokenMalformed, Err: err}
+	}
+
+	if mapClaims, ok := claims.(gojwt.MapClaims); ok {
+		decoded := gojwt.MapClaims{}
+		if err := json.Unmarshal(data, &decoded); err != nil {
+			return &VerificationError{Reason: ErrTokenMalformed, Err: err}
+		}
+		for k, v := range decoded {
+			mapClaims[k] = v
+		}
+	} else if err := json.Unmarshal(data, claims); err != nil {
+		return &VerificationError{Reason: ErrTokenMalformed, Err: err}
+	}
+
+	var raw rawClaims
+	json.Unmarshal(data, &raw)
+
+	if err := gojwt.NewValidator(o.parserOptions()...).Validate(claims); err != nil {
+		return withTokenID(mapError(err), raw.ID)
+	}
+	return withTokenID(o.check(ctx, claims, raw), raw.ID)
+}
+
+// Time based claims PASETO stores as RFC 3339 strings instead of NumericDate.
+var pasetoTimeClaims = []string{"exp", "nbf", "iat"}
+
+func pasetoToken(claims gojwt.Claims) (*paseto.Token, error) {
+	data, err := json.Marshal(claims)
+	if err != nil {
+		return nil, err
+	}
+	payload, err := decodeObject(data)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, claim := range pasetoTimeClaims {
+		number, ok := payload[claim].(json.Number)
+		if !ok {
+			continue
+		}
+		seconds, err := number.Float64()
+		if err != nil {
+			return nil, err
+		}
+		payload[claim] = time.UnixMilli(int64(seconds * 1000)).UTC().Format(time.RFC3339Nano)
+	}
+
+	if data, err = json.Marshal(payload); err != nil {
+		return nil, err
+	}
+	return paseto.NewTokenFromClaimsJSON(data, nil)
+}
+
+func fromPasetoTimes(data []byte) ([]byte, error) {
+	payload, err := decodeObject(data)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, claim := range pasetoTimeClaims {
+		value, ok := payload[claim].(string)
+		if !ok {
+			continue
+		}
+		at, err := time.Parse(time.RFC3339Nano, value)
+		if err != nil {
+			return nil, err
+		}
+		payload[claim] = json.Number(fmt.Sprint(at.Unix()))
+	}
+	return json.Marshal(payload)
+}
+
+func decodeObject(data []byte) (map[string]any, error) {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+
+	var payload map[string]any
+	if err := decoder.Decode(&payload); err != nil {
+		return nil, err
+	}
+	return payload, nil
+}
@@ -0,0 +1,76 @@
+package jwt
+
+import (
+	"crypto/ecdsa"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"strings"
+	"testing"
+)
+
+// The fixtures in testdata are ec.pem encrypted with openssl 3, e.g.
+//
+//	openssl pkcs8 -topk8 -in ec.pem -v2 aes-256-cbc -v2prf hmacWithSHA256 -passout pass:secret
+func TestDecryptPKCS8(t *testing.T) {
+	want := testdataDER(t, "ec.pem")
+
+	tests := []struct {
+		name     string
+		file     string
+		password string
+		err      error
+		errText  string
+	}{
+		{name: "aes-128-cbc sha256", file: "ec_aes128_sha256.pem", password: "secret"},
+		{name: "aes-256-cbc sha256", file: "ec_aes256_sha256.pem", password: "secret"},
+		{name: "aes-128-cbc sha1", file: "ec_aes128_sha1.pem", password: "secret"},
+		{name: "aes-256-cbc sha1", file: "ec_aes256_sha1.pem", password: "secret"},
+		{name: "wrong password", file: "ec_aes256_sha256.pem", password: "wrong", err: errPKCS8Decrypt},
+		{name: "des-ede3-cbc", file: "ec_des3.pem", password: "secret", errText: "unsupported private key cipher"},
+		{name: "hmacWithSHA384", file: "ec_sha384.pem", password: "secret", errText: "unsupported key derivation prf"},
+		{name: "scrypt", file: "ec_scrypt.pem", password: "secret", errText: "unsupported key derivation"},
+		{name: "pbes1", file: "ec_pbes1.pem", password: "secret", errText: "unsupported private key encryption"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			der, err := decryptPKCS8(testdataDER(t, tt.file), []byte(tt.password))
+			switch {
+			case tt.err != nil:
+				if !errors.Is(err, tt.err) {
+					t.Fatalf("err = %v, want %v", err, tt.err)
+				}
+				return
+			case tt.errText != "":
+				if err == nil || !strings.Contains(err.Error(), tt.errText) {
+					t.Fatalf("err = %v, want %q", err, tt.errText)
+				}
+				return
+			case err != nil:
+				t.Fatal(err)
+			}
+
+			key, err := x509.ParsePKCS8PrivateKey(der)
+			if err != nil {
+				t.Fatal(err)
+			}
+			wantKey, err := x509.ParsePKCS8PrivateKey(want)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !key.(*ecdsa.PrivateKey).Equal(wantKey) {
+				t.Fatal("decrypted key differs from ec.pem")
+			}
+		})
+	}
+}
+
+func testdataDER(t *testing.T, name string) []byte {
+	t.Helper()
+	block, _ := pem.Decode(testdataFile(t, name))
+	if block == nil {
+		t.Fatalf("%s: no PEM block", name)
+	}
+	return block.Bytes
+}
@@ -0,0 +1,171 @@
+package jwt
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	gojwt "github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+)
+
+const refreshTokenType = "refresh"
+
+var (
+	ErrRefreshTokenReused  = errors.New("refresh token has already been used")
+	ErrRefreshTokenRevoked = errors.New("refresh token family is revoked")
+)
+
+// Tokens is an access token together with the refresh token that renews it.
+type Tokens struct {
+	AccessToken  string    `json:"access_token"`
+	RefreshToken string    `json:"refresh_token"`
+	ExpiresAt    time.Time `json:"expires_at"`
+}
+
+type refreshClaims struct {
+	Family string `json:"fam"`
+	Type   string `json:"type"`
+	gojwt.RegisteredClaims
+}
+
+type TokenPairConfig struct {
+	AccessTTL  time.Duration // default 15 minutes
+	RefreshTTL time.Duration // default 7 days, extended on every rotation
+	Issuer     string
+
+	// Audience is the "aud" of both tokens, set it to the audience the
+	// verifiers check with WithAudience.
+	Audience []string
+
+	// RefreshJWT signs and verifies refresh tokens, default the EpicJWT of
+	// NewTokenPair. A separate key keeps refresh tokens away from services
+	// that only verify access tokens.
+	RefreshJWT EpicJWT
+
+	// AccessClaims builds the access token claims on issue and on every
+	// refresh. registered is pre-filled with jti, sub, iss, aud, iat and exp
+	// and is expected to be embedded in the returned claims.
+	AccessClaims func(ctx context.Context, registered gojwt.RegisteredClaims) (gojwt.Claims, error)
+}
+
+// TokenPair issues access and refresh tokens on top of an EpicJWT. Refresh
+// tokens are rotated on every use; presenting an already rotated refresh
+// token revokes its whole family. Refresh tokens carry "type": "refresh" and
+// are rejected by Verify, Decode and Middleware as access tokens.
+type TokenPair struct {
+	jwt   EpicJWT
+	store FamilyStore
+	cfg   TokenPairConfig
+}
+
+func NewTokenPair(jwt EpicJWT, store FamilyStore, cfg TokenPairConfig) *TokenPair {
+	if cfg.AccessTTL == 0 {
+		cfg.AccessTTL = 15 * time.Minute
+	}
+	if cfg.RefreshTTL == 0 {
+		cfg.RefreshTTL = 7 * 24 * time.Hour
+	}
+	if cfg.RefreshJWT == nil {
+		cfg.RefreshJWT = jwt
+	}
+	if cfg.AccessClaims == nil {
+		cfg.AccessClaims = func(_ context.Context, registered gojwt.RegisteredClaims) (gojwt.Claims, error) {
+			return registered, nil
+		}
+	}
+	return &TokenPair{jwt: jwt, store: store, cfg: cfg}
+}
+
+// Issue starts a new token family for subject, typically on login.
+func (p *TokenPair) Issue(ctx context.Context, subject string) (*Tokens, error) {
+	family := Family{
+		ID:        uuid.NewString(),
+		Subject:   subject,
+		Current:   uuid.NewString(),
+		ExpiresAt: time.Now().Add(p.cfg.RefreshTTL),
+	}
+	if err := p.store.Create(ctx, family); err != nil {
+		return nil, err
+	}
+	return p.issue(ctx, family)
+}
+
+// Refresh exchanges a refresh token for a new pair and invalidates it.
+func (p *TokenPair) Refresh(ctx context.Context, refreshToken string) (*Tokens, error) {
+	var claims refreshClaims
+	if err := DecodeContext(ctx, p.cfg.RefreshJWT, refreshToken, &claims); err != nil {
+		return nil, err
+	}
+	if claims.Type != refreshTokenType || claims.Family == "" || claims.ID == "" {
+		return nil, reject(ErrInvalidToken, "type")
+	}
+
+	family := Family{
+		ID:        claims.Family,
+		Subject:   claims.Subject,
+		Current:   uuid.NewString(),
+		ExpiresAt: time.Now().Add(p.cfg.RefreshTTL),
+	}
+	if err := p.store.Rotate(ctx, family, claims.ID); err != nil {
+		return nil, err
+	}
+	return p.issue(ctx, family)
+}
+
+// Revoke ends the family of refreshToken, typically on logout. Expired
+// refresh tokens are accepted so a stale session can still be closed.
+func (p *TokenPair) Revoke(ctx context.Context, refreshToken string) error {
+	var claims refreshClaims
+	if err := DecodeContext(ctx, p.cfg.RefreshJWT, refreshToken, &claims); err != nil && !errors.Is(err, ErrTokenExpired) {
+		return err
+	}
+	if claims.Type != refreshTokenType || claims.Family == "" {
+		return reject(ErrInvalidToken, "type")
+	}
+	return p.store.Revoke(ctx, claims.Family)
+}
+
+func (p *TokenPair) issue(ctx context.Context, family Family) (*Tokens, error) {
+	now := time.Now()
+	expiresAt := now.Add(p.cfg.AccessTTL)
+
+	accessClaims, err := p.cfg.AccessClaims(ctx, gojwt.RegisteredClaims{
+		ID:        uuid.NewString(),
+		Subject:   family.Subject,
+		Issuer:    p.cfg.Issuer,
+		Audience:  p.cfg.Audience,
+		IssuedAt:  gojwt.NewNumericDate(now),
+		ExpiresAt: gojwt.NewNumericDate(expiresAt),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	accessToken, err := p.jwt.Sign(accessClaims)
+	if err != nil {
+		return nil, err
+	}
+
+	refreshToken, err := p.cfg.RefreshJWT.Sign(refreshClaims{
+		Family: family.ID,
+		Type:   refreshTokenType,
+		RegisteredClaims: gojwt.RegisteredClaims{
+			ID:        family.Current,
+			Subject:   family.Subject,
+			Issuer:    p.cfg.Issuer,
+			Audience:  p.cfg.Audience,
+			IssuedAt:  gojwt.NewNumericDate(now),
+			ExpiresAt: gojwt.NewNumericDate(family.ExpiresAt),
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &Tokens{
+		AccessToken:  accessToken,
+		RefreshToken: refreshToken,
+		ExpiresAt:    expiresAt,
+	}, nil
+}
@@ -0,0 +1,57 @@
+package jwt
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestRefreshReuseRevokesFamily(t *testing.T) {
+	ctx := context.Background()
+	j := NewHMAC(strings.Repeat("s", MinHMACSecretLength), HS256)
+	pair := NewTokenPair(j, NewMemoryFamilyStore(), TokenPairConfig{})
+
+	issued, err := pair.Issue(ctx, "user-1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	rotated, err := pair.Refresh(ctx, issued.RefreshToken)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The stale token is detected as reused and ends the family, so the
+	// latest refresh token, possibly held by an attacker, is rejected too.
+	if _, err := pair.Refresh(ctx, issued.RefreshToken); !errors.Is(err, ErrRefreshTokenReused) {
+		t.Fatalf("err = %v, want %v", err, ErrRefreshTokenReused)
+	}
+	if _, err := pair.Refresh(ctx, rotated.RefreshToken); !errors.Is(err, ErrRefreshTokenRevoked) {
+		t.Fatalf("err = %v, want %v", err, ErrRefreshTokenRevoked)
+	}
+
+	// Another login is a family of its own.
+	other, err := pair.Issue(ctx, "user-1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := pair.Refresh(ctx, other.RefreshToken); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestRefreshTokenRejectedAsAccessToken(t *testing.T) {
+	j := NewHMAC(strings.Repeat("s", MinHMACSecretLength), HS256)
+	pair := NewTokenPair(j, NewMemoryFamilyStore(), TokenPairConfig{})
+
+	tokens, err := pair.Issue(context.Background(), "user-1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := j.Verify(tokens.AccessToken); err != nil {
+		t.Fatal(err)
+	}
+	if err := j.Verify(tokens.RefreshToken); err == nil {
+		t.Fatal("refresh token accepted as access token")
+	}
+}
@@ -0,0 +1,150 @@
+package jwt
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// RevocationStore is a denylist of tokens that must be rejected before
+// their "exp". Entries expire on their own once the tokens would have.
+type RevocationStore interface {
+	// Revoke denies a single token, e.g. on logout. expiresAt is the token "exp".
+	Revoke(ctx context.Context, jti string, expiresAt time.Time) error
+	IsRevoked(ctx context.Context, jti string) (bool, error)
+
+	// RevokeSubject denies every token of subject issued before the current
+	// second, e.g. for "logout everywhere" or an admin force-logout. ttl
+	// should cover the longest token lifetime.
+	RevokeSubject(ctx context.Context, subject string, ttl time.Duration) error
+	SubjectRevokedAt(ctx context.Context, subject string) (time.Time, bool, error)
+}
+
+// WithRevocation rejects tokens denied by store with ErrTokenRevoked.
+func WithRevocation(store RevocationStore) Option {
+	return func(o *options) {
+		o.revocation = store
+	}
+}
+
+type memoryRevocationStore struct {
+	mu       sync.Mutex
+	tokens   map[string]time.Time
+	subjects map[string]revokedSubject
+}
+
+type revokedSubject struct {
+	revokedAt time.Time
+	expiresAt time.Time
+}
+
+func NewMemoryRevocationStore() RevocationStore {
+	return &memoryRevocationStore{
+		tokens:   map[string]time.Time{},
+		subjects: map[string]revokedSubject{},
+	}
+}
+
+func (m *memoryRevocationStore) Revoke(_ context.Context, jti string, expiresAt time.Time) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.prune()
+	m.tokens[jti] = expiresAt
+	return nil
+}
+
+func (m *memoryRevocationStore) IsRevoked(_ context.Context, jti string) (bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	expiresAt, ok := m.tokens[jti]
+	return ok && time.Now().Before(expiresAt), nil
+}
+
+func (m *memoryRevocationStore) RevokeSubject(_ context.Context, subject string, ttl time.Duration) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.prune()
+	now := time.Now()
+	m.subjects[subject] = revokedSubject{revokedAt: now.Truncate(time.Second), expiresAt: now.Add(ttl)}
+	return nil
+}
+
+func (m *memoryRevocationStore) SubjectRevokedAt(_ context.Context, subject string) (time.Time, bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	revoked, ok := m.subjects[subject]
+	if !ok || time.Now().After(revoked.expiresAt) {
+		return time.Time{}, false, nil
+	}
+	return revoked.revokedAt, true, nil
+}
+
+// prune drops expired entries, caller must hold the lock.
+func (m *memoryRevocationStore) prune() {
+	now := time.Now()
+	for jti, expiresAt := range m.tokens {
+		if now.After(expiresAt) {
+			delete(m.tokens, jti)
+		}
+	}
+	for subject, revoked := range m.subjects {
+		if now.After(revoked.expiresAt) {
+			delete(m.subjects, subject)
+		}
+	}
+}
+
+type redisRevocationStore struct {
+	client redis.UniversalClient
+	prefix string
+}
+
+// NewRedisRevocationStore keeps revoked token ids under prefix+"jti:"+jti and
+// revoked subjects under prefix+"sub:"+subject.
+func NewRedisRevocationStore(client redis.UniversalClient, prefix string) RevocationStore {
+	return &redisRevocationStore{client: client, prefix: prefix}
+}
+
+func (r *redisRevocationStore) Revoke(ctx context.Context, jti string, expiresAt time.Time) error {
+	ttl := time.Until(expiresAt)
+	if ttl <= 0 {
+		return nil
+	}
+	return r.client.Set(ctx, r.prefix+"jti:"+jti, 1, ttl).Err()
+}
+
+func (r *redisRevocationStore) IsRevoked(ctx context.Context, jti string) (bool, error) {
+	n, err := r.client.Exists(ctx, r.prefix+"jti:"+jti).Result()
+	if err != nil {
+		return false, err
+	}
+	return n > 0, nil
+}
+
+func (r *redisRevocationStore) RevokeSubject(ctx context.Context, subject string, ttl time.Duration) error {
+	return r.client.Set(ctx, r.prefix+"sub:"+subject, time.Now().Unix(), ttl).Err()
+}
+
+func (r *redisRevocationStore) SubjectRevokedAt(ctx context.Context, subject string) (time.Time, bool, error) {
+	value, err := r.client.Get(ctx, r.prefix+"sub:"+subject).Result()
+	if errors.Is(err, redis.Nil) {
+		return time.Time{}, false, nil
+	}
+	if err != nil {
+		return time.Time{}, false, err
+	}
+
+	seconds, err := strconv.ParseInt(value, 10, 64)
+	if err != nil {
+		return time.Time{}, false, err
+	}
+	return time.Unix(seconds, 0), true, nil
+}
@@ -0,0 +1,83 @@
+package jwt
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	gojwt "github.com/golang-jwt/jwt/v5"
+)
+
+func TestRevokeSubject(t *testing.T) {
+	ctx := context.Background()
+	store := NewMemoryRevocationStore()
+	j := NewHMAC(strings.Repeat("s", MinHMACSecretLength), HS256, WithRevocation(store))
+
+	sign := func(issuedAt time.Time) string {
+		t.Helper()
+		token, err := j.Sign(gojwt.RegisteredClaims{
+			Subject:   "user-1",
+			IssuedAt:  gojwt.NewNumericDate(issuedAt),
+			ExpiresAt: gojwt.NewNumericDate(time.Now().Add(time.Hour)),
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return token
+	}
+
+	before := sign(time.Now().Add(-time.Minute))
+	if err := j.Verify(before); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := store.RevokeSubject(ctx, "user-1", time.Hour); err != nil {
+		t.Fatal(err)
+	}
+	if err := j.Verify(before); !errors.Is(err, ErrTokenRevoked) {
+		t.Fatalf("token issued before the revocation: err = %v, want %v", err, ErrTokenRevoked)
+	}
+
+	// Issued in the second of the revocation or later, e.g. after logging in again.
+	if err := j.Verify(sign(time.Now())); err != nil {
+		t.Fatalf("token issued after the revocation: %v", err)
+	}
+
+	// Without "iat" a token cannot prove it was issued after the revocation.
+	noIssuedAt, err := j.Sign(gojwt.RegisteredClaims{Subject: "user-1"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := j.Verify(noIssuedAt); !errors.Is(err, ErrTokenRevoked) {
+		t.Fatalf("token without iat: err = %v, want %v", err, ErrTokenRevoked)
+	}
+
+	// Other subjects are not affected.
+	other, err := j.Sign(gojwt.RegisteredClaims{Subject: "user-2", IssuedAt: gojwt.NewNumericDate(time.Now().Add(-time.Minute))})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := j.Verify(other); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestRevokeToken(t *testing.T) {
+	ctx := context.Background()
+	store := NewMemoryRevocationStore()
+	j := NewHMAC(strings.Repeat("s", MinHMACSecretLength), HS256, WithRevocation(store))
+
+	expiresAt := time.Now().Add(time.Hour)
+	token, err := j.Sign(gojwt.RegisteredClaims{ID: "jti-1", ExpiresAt: gojwt.NewNumericDate(expiresAt)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := store.Revoke(ctx, "jti-1", expiresAt); err != nil {
+		t.Fatal(err)
+	}
+	if err := j.Verify(token); !errors.Is(err, ErrTokenRevoked) {
+		t.Fatalf("err = %v, want %v", err, ErrTokenRevoked)
+	}
+}
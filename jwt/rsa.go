@@ -1,9 +1,9 @@
 package jwt
 
 import (
+	"context"
 	"crypto/rsa"
 	"errors"
-	"fmt"
 
 	gojwt "github.com/golang-jwt/jwt/v5"
 )
@@ -12,13 +12,17 @@ type epicRSA struct {
 	algorithm  gojwt.SigningMethod
 	privateKey *rsa.PrivateKey
 	publicKey  *rsa.PublicKey
+	opts       options
 }
 
-func NewRSA(private *rsa.PrivateKey, public *rsa.PublicKey, algorithm gojwt.SigningMethod) EpicJWT {
+// NewRSA does not check the key size, New rejects keys smaller than
+// MinRSAKeyBits.
+func NewRSA(private *rsa.PrivateKey, public *rsa.PublicKey, algorithm gojwt.SigningMethod, opts ...Option) EpicJWT {
 	return &epicRSA{
 		algorithm:  algorithm,
 		privateKey: private,
 		publicKey:  public,
+		opts:       newOptions(opts),
 	}
 }
 
@@ -35,64 +39,22 @@ func (r *epicRSA) Sign(claims gojwt.Claims) (string, error) {
 }
 
 func (r *epicRSA) Verify(tokenStr string) error {
-	token, err := gojwt.Parse(tokenStr, func(token *gojwt.Token) (any, error) {
-		if _, ok := token.Method.(*gojwt.SigningMethodRSA); !ok {
-			return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
-		}
-		return r.publicKey, nil
-	})
-
-	if err != nil {
-		switch {
-		case errors.Is(err, gojwt.ErrTokenExpired):
-			return ErrTokenExpired
-		case errors.Is(err, gojwt.ErrTokenMalformed):
-			return ErrTokenMalformed
-		case errors.Is(err, gojwt.ErrTokenNotValidYet):
-			return ErrTokenNotValidYet
-		case errors.Is(err, gojwt.ErrTokenSignatureInvalid):
-			return ErrTokenSignatureInvalid
-		case errors.Is(err, ErrUnexpectedSigningMethod):
-			return ErrUnexpectedSigningMethod
-		default:
-			return fmt.Errorf("failed to parse token: %w", err)
-		}
-	}
-
-	if !token.Valid {
-		return ErrInvalidToken
-	}
-	return nil
+	return r.opts.parse(context.Background(), tokenStr, gojwt.MapClaims{}, r.keyFunc)
 }
 
 func (r *epicRSA) Decode(tokenStr string, claims gojwt.Claims) error {
-	token, err := gojwt.ParseWithClaims(tokenStr, claims, func(token *gojwt.Token) (any, error) {
-		if _, ok := token.Method.(*gojwt.SigningMethodRSA); !ok {
-			return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
-		}
-		return r.publicKey, nil
-	})
+	return r.DecodeContext(context.Background(), tokenStr, claims)
+}
 
-	if err != nil {
-		switch {
-		case errors.Is(err, gojwt.ErrTokenExpired):
-			return ErrTokenExpired
-		case errors.Is(err, gojwt.ErrTokenMalformed):
-			return ErrTokenMalformed
-		case errors.Is(err, gojwt.ErrTokenNotValidYet):
-			return ErrTokenNotValidYet
-		case errors.Is(err, gojwt.ErrTokenSignatureInvalid):
-			return ErrTokenSignatureInvalid
-		case errors.Is(err, ErrUnexpectedSigningMethod):
-			return ErrUnexpectedSigningMethod
-		default:
-			return fmt.Errorf("failed to parse token: %w", err)
-		}
-	}
+func (r *epicRSA) DecodeContext(ctx context.Context, tokenStr string, claims gojwt.Claims) error {
+	return r.opts.parse(ctx, tokenStr, claims, r.keyFunc)
+}
 
-	if !token.Valid {
-		return ErrInvalidToken
+func (r *epicRSA) keyFunc(token *gojwt.Token) (any, error) {
+	switch token.Method.(type) {
+	case *gojwt.SigningMethodRSA, *gojwt.SigningMethodRSAPSS:
+		return r.publicKey, nil
+	default:
+		return nil, unexpectedMethod(token)
 	}
-
-	return nil
 }
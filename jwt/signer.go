@@ -0,0 +1,154 @@
+package jwt
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/asn1"
+	"errors"
+	"fmt"
+	"math/big"
+
+	gojwt "github.com/golang-jwt/jwt/v5"
+)
+
+type epicSigner struct {
+	algorithm gojwt.SigningMethod
+	signer    crypto.Signer
+	publicKey crypto.PublicKey
+	opts      options
+}
+
+// NewSigner signs RS*, PS* and ES* tokens with signer, so the private key can
+// stay in an HSM, a cloud KMS or a separate signing process. Tokens are
+// verified locally with signer.Public().
+func NewSigner(signer crypto.Signer, algorithm gojwt.SigningMethod, opts ...Option) (EpicJWT, error) {
+	public := signer.Public()
+	switch method := algorithm.(type) {
+	case *gojwt.SigningMethodRSA, *gojwt.SigningMethodRSAPSS:
+		if _, ok := public.(*rsa.PublicKey); !ok {
+			return nil, fmt.Errorf("%s requires an rsa signer", algorithm.Alg())
+		}
+	case *gojwt.SigningMethodECDSA:
+		key, ok := public.(*ecdsa.PublicKey)
+		if !ok {
+			return nil, fmt.Errorf("%s requires an ecdsa signer", algorithm.Alg())
+		}
+		if err := checkCurve(key, method); err != nil {
+			return nil, err
+		}
+	default:
+		return nil, errors.New("unsupported algorithm")
+	}
+
+	return &epicSigner{
+		algorithm: algorithm,
+		signer:    signer,
+		publicKey: public,
+		opts:      newOptions(opts),
+	}, nil
+}
+
+func (s *epicSigner) Sign(claims gojwt.Claims) (string, error) {
+	return signWith(gojwt.NewWithClaims(s.algorithm, claims), s.signer)
+}
+
+// signWith completes token with a signature made by signer.
+func signWith(token *gojwt.Token, signer crypto.Signer) (string, error) {
+	signingString, err := token.SigningString()
+	if err != nil {
+		return "", err
+	}
+
+	sig, err := signatureOf(token.Method, signer, []byte(signingString))
+	if err != nil {
+		return "", err
+	}
+	return signingString + "." + token.EncodeSegment(sig), nil
+}
+
+// signatureOf produces the JWS signature, which for ECDSA is r || s rather
+// than the ASN.1 encoding returned by crypto.Signer.
+func signatureOf(algorithm gojwt.SigningMethod, signer crypto.Signer, data []byte) ([]byte, error) {
+	switch method := algorithm.(type) {
+	case *gojwt.SigningMethodRSAPSS:
+		return signer.Sign(rand.Reader, digest(method.Hash, data), &rsa.PSSOptions{
+			SaltLength: rsa.PSSSaltLengthEqualsHash,
+			Hash:       method.Hash,
+		})
+	case *gojwt.SigningMethodRSA:
+		return signer.Sign(rand.Reader, digest(method.Hash, data), method.Hash)
+	case *gojwt.SigningMethodECDSA:
+		der, err := signer.Sign(rand.Reader, digest(method.Hash, data), method.Hash)
+		if err != nil {
+			return nil, err
+		}
+		var sig struct{ R, S *big.Int }
+		if _, err := asn1.Unmarshal(der, &sig); err != nil {
+			return nil, fmt.Errorf("failed to decode ecdsa signature: %w", err)
+		}
+		out := make([]byte, 2*method.KeySize)
+		sig.R.FillBytes(out[:method.KeySize])
+		sig.S.FillBytes(out[method.KeySize:])
+		return out, nil
+	default:
+		return nil, errors.New("unsupported algorithm")
+	}
+}
+
+func digest(hash crypto.Hash, data []byte) []byte {
+	h := hash.New()
+	h.Write(data)
+	return h.Sum(nil)
+}
+
+func (s *epicSigner) Verify(tokenStr string) error {
+	return s.opts.parse(context.Background(), tokenStr, gojwt.MapClaims{}, s.keyFunc)
+}
+
+func (s *epicSigner) Decode(tokenStr string, claims gojwt.Claims) error {
+	return s.DecodeContext(context.Background(), tokenStr, claims)
+}
+
+func (s *epicSigner) DecodeContext(ctx context.Context, tokenStr string, claims gojwt.Claims) error {
+	return s.opts.parse(ctx, tokenStr, claims, s.keyFunc)
+}
+
+func (s *epicSigner) keyFunc(token *gojwt.Token) (any, error) {
+	switch token.Method.(type) {
+	case *gojwt.SigningMethodRSA, *gojwt.SigningMethodRSAPSS:
+		if _, ok := s.publicKey.(*rsa.PublicKey); ok {
+			return s.publicKey, nil
+		}
+	case *gojwt.SigningMethodECDSA:
+		if _, ok := s.publicKey.(*ecdsa.PublicKey); ok {
+			return s.publicKey, nil
+		}
+	}
+	return nil, unexpectedMethod(token)
+}
+
+// NewMemorySigner generates an ephemeral in-memory key suited to algorithm,
+// a stand-in for an external signer in tests and local development.
+func NewMemorySigner(algorithm gojwt.SigningMethod) (crypto.Signer, error) {
+	switch method := algorithm.(type) {
+	case *gojwt.SigningMethodRSA, *gojwt.SigningMethodRSAPSS:
+		return rsa.GenerateKey(rand.Reader, MinRSAKeyBits)
+	case *gojwt.SigningMethodECDSA:
+		var curve elliptic.Curve
+		switch method.CurveBits {
+		case 256:
+			curve = elliptic.P256()
+		case 384:
+			curve = elliptic.P384()
+		default:
+			curve = elliptic.P521()
+		}
+		return ecdsa.GenerateKey(curve, rand.Reader)
+	default:
+		return nil, errors.New("unsupported algorithm")
+	}
+}
@@ -0,0 +1,90 @@
+package jwt
+
+import (
+	"crypto"
+	"net"
+	"path/filepath"
+	"testing"
+
+	gojwt "github.com/golang-jwt/jwt/v5"
+)
+
+var signerAlgorithms = []gojwt.SigningMethod{RS256, RS512, PS256, PS512, ES256, ES384, ES512}
+
+// signatureOf must produce what the stock golang-jwt methods verify, r || s
+// instead of ASN.1 DER for ECDSA.
+func TestSignatureOf(t *testing.T) {
+	data := []byte("eyJhbGciOiJub25lIn0.eyJzdWIiOiJ1c2VyIn0")
+
+	for _, algorithm := range signerAlgorithms {
+		t.Run(algorithm.Alg(), func(t *testing.T) {
+			signer, err := NewMemorySigner(algorithm)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			sig, err := signatureOf(algorithm, signer, data)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if method, ok := algorithm.(*gojwt.SigningMethodECDSA); ok && len(sig) != 2*method.KeySize {
+				t.Fatalf("signature is %d bytes, want %d", len(sig), 2*method.KeySize)
+			}
+			if err := algorithm.Verify(string(data), sig, signer.Public()); err != nil {
+				t.Fatal(err)
+			}
+		})
+	}
+}
+
+func TestSocketSigner(t *testing.T) {
+	for _, algorithm := range signerAlgorithms {
+		t.Run(algorithm.Alg(), func(t *testing.T) {
+			key, err := NewMemorySigner(algorithm)
+			if err != nil {
+				t.Fatal(err)
+			}
+			signer := serveTestSigner(t, key)
+
+			j, err := NewSigner(signer, algorithm)
+			if err != nil {
+				t.Fatal(err)
+			}
+			token, err := j.Sign(gojwt.RegisteredClaims{Subject: "user"})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			parsed, err := gojwt.Parse(token, func(*gojwt.Token) (any, error) {
+				return key.Public(), nil
+			}, gojwt.WithValidMethods([]string{algorithm.Alg()}))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if sub, _ := parsed.Claims.GetSubject(); sub != "user" {
+				t.Fatalf("sub = %q, want user", sub)
+			}
+		})
+	}
+}
+
+// serveTestSigner runs ServeSigner for key on a Unix socket and connects a
+// SocketSigner to it.
+func serveTestSigner(t *testing.T, key crypto.Signer) *SocketSigner {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), "signer.sock")
+	l, err := net.Listen("unix", path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	go ServeSigner(l, key)
+	t.Cleanup(func() { l.Close() })
+
+	signer, err := NewSocketSigner(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { signer.Close() })
+	return signer
+}
@@ -0,0 +1,149 @@
+package jwt
+
+import (
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"errors"
+	"io"
+	"net"
+	"net/rpc"
+	"sync"
+)
+
+// SignerService exposes a crypto.Signer over net/rpc, standing in for a
+// signing daemon that keeps the private key out of the service process.
+type SignerService struct {
+	signer crypto.Signer
+}
+
+type SignArgs struct {
+	Digest []byte
+	Hash   crypto.Hash
+	PSS    bool // sign with RSASSA-PSS, salt length equal to the hash
+}
+
+func (s *SignerService) Public(_ struct{}, reply *[]byte) error {
+	der, err := x509.MarshalPKIXPublicKey(s.signer.Public())
+	if err != nil {
+		return err
+	}
+	*reply = der
+	return nil
+}
+
+func (s *SignerService) Sign(args SignArgs, reply *[]byte) error {
+	var opts crypto.SignerOpts = args.Hash
+	if args.PSS {
+		opts = &rsa.PSSOptions{SaltLength: rsa.PSSSaltLengthEqualsHash, Hash: args.Hash}
+	}
+	sig, err := s.signer.Sign(rand.Reader, args.Digest, opts)
+	if err != nil {
+		return err
+	}
+	*reply = sig
+	return nil
+}
+
+// ServeSigner answers signing requests for signer on l, typically a Unix
+// socket, until l is closed.
+//
+//	l, _ := net.Listen("unix", "/run/epicjwt/signer.sock")
+//	go jwt.ServeSigner(l, key)
+func ServeSigner(l net.Listener, signer crypto.Signer) error {
+	server := rpc.NewServer()
+	if err := server.RegisterName("Signer", &SignerService{signer: signer}); err != nil {
+		return err
+	}
+	for {
+		conn, err := l.Accept()
+		if err != nil {
+			if errors.Is(err, net.ErrClosed) {
+				return nil
+			}
+			return err
+		}
+		go server.ServeConn(conn)
+	}
+}
+
+// SocketSigner is a crypto.Signer whose key is held by a signing daemon,
+// see ServeSigner.
+type SocketSigner struct {
+	network string
+	address string
+	public  crypto.PublicKey
+
+	mu     sync.Mutex
+	client *rpc.Client
+}
+
+// NewSocketSigner connects to the signing daemon listening on the Unix
+// socket at path and fetches its public key.
+func NewSocketSigner(path string) (*SocketSigner, error) {
+	s := &SocketSigner{network: "unix", address: path}
+
+	var der []byte
+	if err := s.call("Signer.Public", struct{}{}, &der); err != nil {
+		return nil, err
+	}
+	public, err := x509.ParsePKIXPublicKey(der)
+	if err != nil {
+		return nil, err
+	}
+	s.public = public
+	return s, nil
+}
+
+func (s *SocketSigner) Public() crypto.PublicKey {
+	return s.public
+}
+
+func (s *SocketSigner) Sign(_ io.Reader, digest []byte, opts crypto.SignerOpts) ([]byte, error) {
+	args := SignArgs{Digest: digest, Hash: opts.HashFunc()}
+	if _, ok := opts.(*rsa.PSSOptions); ok {
+		args.PSS = true
+	}
+
+	var sig []byte
+	if err := s.call("Signer.Sign", args, &sig); err != nil {
+		return nil, err
+	}
+	return sig, nil
+}
+
+// call reconnects once when the daemon has been restarted.
+func (s *SocketSigner) call(method string, args any, reply any) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for attempt := 0; ; attempt++ {
+		if s.client == nil {
+			client, err := rpc.Dial(s.network, s.address)
+			if err != nil {
+				return err
+			}
+			s.client = client
+		}
+
+		err := s.client.Call(method, args, reply)
+		if !errors.Is(err, rpc.ErrShutdown) && !errors.Is(err, io.ErrUnexpectedEOF) || attempt > 0 {
+			return err
+		}
+		s.client.Close()
+		s.client = nil
+	}
+}
+
+func (s *SocketSigner) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.client == nil {
+		return nil
+	}
+	err := s.client.Close()
+	s.client = nil
+	return err
+}
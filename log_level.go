@@ -0,0 +1,100 @@
+package pkgep
+
+import (
+	"strings"
+
+	"github.com/epicconsult/pkgep/logger"
+	"github.com/gofiber/fiber/v2"
+)
+
+// LogLevels is the response of the log level endpoints.
+type LogLevels struct {
+	Level      logger.Level            `json:"level"`
+	Components map[string]logger.Level `json:"components"`
+}
+
+// LogLevelChange is the body of PUT /admin/log-level. Without Component the
+// level of every logger changes. An empty Level removes the override of
+// Component.
+type LogLevelChange struct {
+	Component string `json:"component"`
+	Level     string `json:"level"`
+}
+
+// LogLevelRoutes serves GET and PUT /admin/log-level for levels, to callers
+// holding any of roles, "admin" by default. router must verify the token
+// first, every change is logged for audit.
+//
+//	admin := app.Group("", pkgep.JWTProtected())
+//	pkgep.LogLevelRoutes(admin, logrus.Levels())
+func LogLevelRoutes(router fiber.Router, levels *logger.Levels, roles ...string) {
+	if len(roles) == 0 {
+		roles = []string{"admin"}
+	}
+	requireRoles := RequireRoles(roles...)
+
+	router.Get("/admin/log-level", requireRoles, func(c *fiber.Ctx) error {
+		return SuccessResponse(c, logLevelsOf(levels))
+	})
+
+	router.Put("/admin/log-level", requireRoles, func(c *fiber.Ctx) error {
+		var change LogLevelChange
+		if err := c.BodyParser(&change); err != nil {
+			return ErrorResponse(c, BadRequest, err.Error())
+		}
+		change.Component = strings.TrimSpace(change.Component)
+
+		if change.Component != "" && strings.TrimSpace(change.Level) == "" {
+			old := levels.For(change.Component)
+			levels.ClearOverride(change.Component)
+			auditLogLevel(c, change.Component, old, levels.For(change.Component))
+			return SuccessResponse(c, logLevelsOf(levels))
+		}
+
+		level, err := logger.ParseLevel(change.Level)
+		if err != nil {
+			return ErrorResponse(c, BadRequest, err.Error())
+		}
+
+		if change.Component != "" {
+			old := levels.For(change.Component)
+			levels.SetOverride(change.Component, level)
+			auditLogLevel(c, change.Component, old, level)
+			return SuccessResponse(c, logLevelsOf(levels))
+		}
+
+		old := levels.Level()
+		levels.SetLevel(level)
+		auditLogLevel(c, "", old, level)
+		return SuccessResponse(c, logLevelsOf(levels))
+	})
+}
+
+func logLevelsOf(levels *logger.Levels) LogLevels {
+	return LogLevels{Level: levels.Level(), Components: levels.Overrides()}
+}
+
+// auditLogLevel records who changed a level, whatever the level when the
+// logger is an ExtendedLogger.
+func auditLogLevel(c *fiber.Ctx, component string, old, level logger.Level) {
+	if logger.Logger == nil {
+		return
+	}
+	ctx := logger.With(c.UserContext(),
+		"log_component", component,
+		"old_level", old.String(),
+		"new_level", level.String(),
+		"ip", c.IP(),
+	)
+	if sub, ok := authorizer.subClaims(c); ok {
+		ctx = logger.With(ctx, "changed_by", sub.UserID, "changed_by_email", sub.Email)
+		if sub.Impersonated() {
+			ctx = logger.With(ctx, "actor", sub.Actor)
+		}
+	}
+	if l, ok := logger.Logger.(logger.ExtendedLogger); ok {
+		l.Audit(ctx, "log level changed")
+		return
+	}
+	logger.Logger.Warn(ctx, "log level changed")
+}
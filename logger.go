@@ -23,6 +23,7 @@ type Logger struct {
 	ParID         string
 	Role          string
 	AuthType      string
+	ActorID       string
 	Path          string
 	Method        string
 }
@@ -98,6 +99,10 @@ func SetHeaderLog(token *VerifiedToken, c *fiber.Ctx) {
 	logr.ParID = strconv.Itoa(token.Sub.ParID)
 	logr.Role = token.Sub.Role
 	logr.AuthType = token.Sub.AuthType
+	logr.ActorID = ""
+	if act := token.subClaims().Actor; act != nil {
+		logr.ActorID = act.Subject
+	}
 	logr.Path = c.Path()
 	logr.Method = c.Method()
 }
@@ -114,6 +119,7 @@ func (l *Logger) LogInformation(Action LogAction, args ...interface{}) {
 			"methodName":        l.Method,
 			"deviceID":          l.DeviceID,
 			"userID":            l.UserID,
+			"actorID":           l.ActorID,
 			"parID":             l.ParID,
 			"deviceName":        l.AuthType,
 			"transactionID":     l.TransactionID,
@@ -126,6 +132,7 @@ func (l *Logger) LogInformation(Action LogAction, args ...interface{}) {
 			"methodName":        l.Method,
 			"deviceID":          l.DeviceID,
 			"userID":            l.UserID,
+			"actorID":           l.ActorID,
 			"parID":             l.ParID,
 			"deviceName":        l.AuthType,
 			"transactionID":     l.TransactionID,
@@ -138,6 +145,7 @@ func (l *Logger) LogInformation(Action LogAction, args ...interface{}) {
 			"methodName":        l.Method,
 			"deviceID":          l.DeviceID,
 			"userID":            l.UserID,
+			"actorID":           l.ActorID,
 			"parID":             l.ParID,
 			"deviceName":        l.AuthType,
 			"transactionID":     l.TransactionID,
@@ -150,6 +158,7 @@ func (l *Logger) LogInformation(Action LogAction, args ...interface{}) {
 			"methodName":        l.Method,
 			"deviceID":          l.DeviceID,
 			"userID":            l.UserID,
+			"actorID":           l.ActorID,
 			"parID":             l.ParID,
 			"deviceName":        l.AuthType,
 			"transactionID":     l.TransactionID,
@@ -161,6 +170,7 @@ func (l *Logger) LogInformation(Action LogAction, args ...interface{}) {
 			"componentName":     componentName,
 			"deviceID":          l.DeviceID,
 			"userID":            l.UserID,
+			"actorID":           l.ActorID,
 			"parID":             l.ParID,
 			"deviceName":        l.AuthType,
 			"transactionID":     l.TransactionID,
@@ -204,6 +214,7 @@ func (l Logger) Trace(ctx context.Context, begin time.Time, fc func() (string, i
 			"componentName":     componentName,
 			"deviceID":          l.DeviceID,
 			"userID":            l.UserID,
+			"actorID":           l.ActorID,
 			"parID":             l.ParID,
 			"deviceName":        l.AuthType,
 			"transactionID":     l.TransactionID,
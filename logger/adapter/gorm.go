@@ -2,6 +2,7 @@ package adapter
 
 import (
 	"context"
+	"os"
 	"time"
 
 	coreLogger "github.com/epicconsult/pkgep/logger"
@@ -18,9 +19,11 @@ type GormResponse struct {
 type GormLogger struct {
 	epicLogger coreLogger.EpicLogger
 	logLevel   logger.LogLevel
+	fields     []any // bound by With and Named when epicLogger is no ExtendedLogger
+	component  string
 }
 
-func NewGormLogger(epicLogger coreLogger.EpicLogger) logger.Interface {
+func NewGormLogger(epicLogger coreLogger.EpicLogger) *GormLogger {
 	return &GormLogger{
 		epicLogger: epicLogger,
 		logLevel:   logger.Info,
@@ -33,15 +36,70 @@ func (g *GormLogger) LogMode(level logger.LogLevel) logger.Interface {
 }
 
 func (g *GormLogger) Info(ctx context.Context, msg string, data ...interface{}) {
-	g.epicLogger.Info(ctx, msg, data...)
+	g.epicLogger.Info(g.context(ctx), msg, data...)
 }
 
 func (g *GormLogger) Warn(ctx context.Context, msg string, data ...interface{}) {
-	g.epicLogger.Warn(ctx, msg, data...)
+	g.epicLogger.Warn(g.context(ctx), msg, data...)
 }
 
 func (g *GormLogger) Error(ctx context.Context, msg string, data ...interface{}) {
-	g.epicLogger.Error(ctx, msg, data...)
+	g.epicLogger.Error(g.context(ctx), msg, data...)
+}
+
+// Debug logs at Trace level when the EpicLogger has no Debug.
+func (g *GormLogger) Debug(ctx context.Context, msg string, data ...interface{}) {
+	if l, ok := g.epicLogger.(coreLogger.ExtendedLogger); ok {
+		l.Debug(g.context(ctx), msg, data...)
+		return
+	}
+	g.epicLogger.Trace(g.context(ctx), msg, data...)
+}
+
+// Fatal logs then exits the process with status 1.
+func (g *GormLogger) Fatal(ctx context.Context, msg string, data ...interface{}) {
+	if l, ok := g.epicLogger.(coreLogger.ExtendedLogger); ok {
+		l.Fatal(g.context(ctx), msg, data...)
+		return
+	}
+	g.epicLogger.Error(g.context(ctx), msg, data...)
+	os.Exit(1)
+}
+
+// With returns a child logger adding fields to every entry, in the forms
+// accepted by logger.With.
+//
+//	db.Session(&gorm.Session{Logger: gormLogger.With("tenant", tenantID)})
+func (g *GormLogger) With(fields ...interface{}) *GormLogger {
+	child := *g
+	if l, ok := g.epicLogger.(coreLogger.ExtendedLogger); ok {
+		child.epicLogger = l.With(fields...)
+		return &child
+	}
+	child.fields = append(g.fields[:len(g.fields):len(g.fields)], fields...)
+	return &child
+}
+
+// Named returns a child logger for component, logged as "component". With
+// an ExtendedLogger the level overrides of component apply.
+func (g *GormLogger) Named(component string) *GormLogger {
+	name := component
+	if g.component != "" {
+		name = g.component + "." + component
+	}
+	if l, ok := g.epicLogger.(coreLogger.ExtendedLogger); ok {
+		child := *g
+		child.epicLogger = l.Named(component)
+		child.component = name
+		return &child
+	}
+	child := g.With("component", name)
+	child.component = name
+	return child
+}
+
+func (g *GormLogger) context(ctx context.Context) context.Context {
+	return coreLogger.With(ctx, g.fields...)
 }
 
 func (g *GormLogger) Trace(ctx context.Context, begin time.Time, fc func() (string, int64), err error) {
@@ -61,7 +119,8 @@ func (g *GormLogger) Trace(ctx context.Context, begin time.Time, fc func() (stri
 		Time: duration.String(),
 	}
 
-	logCtx := context.WithValue(context.Background(), coreLogger.LogHeader, resp)
+	// Keep the fields of the request that ran the query.
+	logCtx := coreLogger.With(g.context(ctx), resp)
 
 	// Log response.
 	if err != nil {
@@ -0,0 +1,37 @@
+package adapter
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	coreLogger "github.com/epicconsult/pkgep/logger"
+)
+
+func TestGormLoggerNamedOverride(t *testing.T) {
+	var buf bytes.Buffer
+	l := coreLogger.NewLogrus(coreLogger.WithOutput(&buf))
+	gormLogger := NewGormLogger(l).Named("gorm")
+	ctx := context.Background()
+	query := func() (string, int64) { return "SELECT 1", 1 }
+
+	gormLogger.Trace(ctx, time.Now(), query, nil)
+	if !strings.Contains(buf.String(), `"component":"gorm"`) {
+		t.Fatalf("expected a query entry for component gorm, got %q", buf.String())
+	}
+
+	buf.Reset()
+	l.Levels().SetOverride("gorm", coreLogger.ErrorLevel)
+	gormLogger.Info(ctx, "connected")
+	gormLogger.Trace(ctx, time.Now(), query, nil)
+	if buf.Len() != 0 {
+		t.Fatalf("override on gorm let through %q", buf.String())
+	}
+
+	gormLogger.Error(ctx, "connection lost")
+	if !strings.Contains(buf.String(), "connection lost") {
+		t.Fatalf("expected the error entry, got %q", buf.String())
+	}
+}
@@ -0,0 +1,65 @@
+package logger
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"testing"
+)
+
+type benchHeader struct {
+	TraceID string `json:"traceID"`
+	UserID  int    `json:"userID"`
+	Path    string `json:"path"`
+}
+
+func benchmarkLogger(b *testing.B, l EpicLogger) {
+	ctx := context.WithValue(context.Background(), LogHeader, benchHeader{
+		TraceID: "9f8b7c1e-4a5d-4e2f-8c3b-1d2e3f4a5b6c",
+		UserID:  42,
+		Path:    "/api/v1/orders",
+	})
+
+	b.Run("Info", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			l.Info(ctx, "order created")
+		}
+	})
+	b.Run("InfoWithAction", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			l.InfoWithAction(ctx, OUTBOUND, "order created", " in ", 12)
+		}
+	})
+	// With converts the LogHeader value once, not on every entry.
+	withCtx := With(ctx, "order_id", 7)
+	b.Run("InfoWith", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			l.Info(withCtx, "order created")
+		}
+	})
+	b.Run("Disabled", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			l.Trace(ctx, "order created")
+		}
+	})
+}
+
+func BenchmarkLogrus(b *testing.B) {
+	benchmarkLogger(b, NewLogrus(WithOutput(io.Discard)))
+}
+
+func BenchmarkZerolog(b *testing.B) {
+	benchmarkLogger(b, NewZerolog(WithOutput(io.Discard)))
+}
+
+func BenchmarkZap(b *testing.B) {
+	benchmarkLogger(b, NewZap(WithOutput(io.Discard)))
+}
+
+func BenchmarkSlog(b *testing.B) {
+	benchmarkLogger(b, NewSlog(slog.NewJSONHandler(io.Discard, &slog.HandlerOptions{ReplaceAttr: SlogReplaceAttr})))
+}
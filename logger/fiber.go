@@ -0,0 +1,206 @@
+package logger
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+)
+
+// DefaultRedactKeys are the body fields replaced by "[REDACTED]" unless
+// MiddlewareConfig.RedactKeys is set. Keys are matched case-insensitively.
+var DefaultRedactKeys = []string{
+	"password", "secret", "token", "access_token", "refresh_token",
+	"authorization", "api_key", "client_secret",
+}
+
+const requestIDLocal = "logger.request_id"
+
+// Longer incoming ids are replaced, they end up in every log line.
+const maxRequestIDLength = 128
+
+type MiddlewareConfig struct {
+	// Logger defaults to the Logger registered with SetLogger.
+	Logger EpicLogger
+
+	// RequestIDHeader carries the transaction id, taken from the request
+	// when present and set on the response. Default "X-Request-ID".
+	RequestIDHeader string
+
+	// CaptureBody adds JSON and form bodies up to MaxBodySize bytes to the
+	// logs, with RedactKeys hidden. Other bodies are logged as their size.
+	CaptureBody bool
+	MaxBodySize int // default 4 KB
+	RedactKeys  []string
+
+	// Claims returns the claims to merge into the header, e.g. the verified
+	// JWT in c.Locals. It is called before each log line, so register the
+	// middleware after the JWT middleware for the INBOUND line to carry them.
+	Claims func(c *fiber.Ctx) any
+
+	// Skip bypasses logging, e.g. for health checks.
+	Skip func(c *fiber.Ctx) bool
+}
+
+func (cfg MiddlewareConfig) withDefaults() MiddlewareConfig {
+	if cfg.RequestIDHeader == "" {
+		cfg.RequestIDHeader = fiber.HeaderXRequestID
+	}
+	if cfg.MaxBodySize == 0 {
+		cfg.MaxBodySize = 4 << 10
+	}
+	if cfg.RedactKeys == nil {
+		cfg.RedactKeys = DefaultRedactKeys
+	}
+	return cfg
+}
+
+// Middleware logs every request as INBOUND and its response as OUTBOUND.
+// transaction_id, method and path are added to the user context with With,
+// so handler logs share the transaction id.
+//
+//	app.Use(logger.Middleware(logger.MiddlewareConfig{CaptureBody: true}))
+func Middleware(config ...MiddlewareConfig) fiber.Handler {
+	var cfg MiddlewareConfig
+	if len(config) > 0 {
+		cfg = config[0]
+	}
+	cfg = cfg.withDefaults()
+
+	redact := make(map[string]bool, len(cfg.RedactKeys))
+	for _, key := range cfg.RedactKeys {
+		redact[strings.ToLower(key)] = true
+	}
+
+	return func(c *fiber.Ctx) error {
+		if cfg.Skip != nil && cfg.Skip(c) {
+			return c.Next()
+		}
+
+		// fiber reuses its buffers, the header may outlive the request in
+		// goroutines started by handlers.
+		requestID := strings.Clone(c.Get(cfg.RequestIDHeader))
+		if requestID == "" || len(requestID) > maxRequestIDLength {
+			requestID = uuid.NewString()
+		}
+		c.Set(cfg.RequestIDHeader, requestID)
+		c.Locals(requestIDLocal, requestID)
+
+		ctx := With(c.UserContext(),
+			"transaction_id", requestID,
+			"method", strings.Clone(c.Method()),
+			"path", strings.Clone(c.Path()),
+		)
+		ctx = cfg.withClaims(c, ctx)
+		c.SetUserContext(ctx)
+
+		l := cfg.Logger
+		if l == nil {
+			l = Logger
+		}
+
+		start := time.Now()
+		if l != nil {
+			inbound := With(ctx, "ip", c.IP(), "user_agent", c.Get(fiber.HeaderUserAgent))
+			if cfg.CaptureBody && len(c.Body()) > 0 {
+				inbound = With(inbound, "request_body", captureBody(c.Body(), string(c.Request().Header.ContentType()), cfg.MaxBodySize, redact))
+			}
+			l.InfoWithAction(inbound, INBOUND, c.Method()+" "+c.Path())
+		}
+
+		err := c.Next()
+		if err != nil {
+			// Run the error handler now, as fiber's logger does, so the
+			// logged status is the one the client gets.
+			if handlerErr := c.App().ErrorHandler(c, err); handlerErr != nil {
+				_ = c.SendStatus(fiber.StatusInternalServerError)
+			}
+		}
+
+		if l != nil {
+			body := c.Response().Body()
+			outbound := With(cfg.withClaims(c, ctx),
+				"status", c.Response().StatusCode(),
+				"latency", time.Since(start).String(),
+				"size", len(body),
+			)
+			if cfg.CaptureBody && len(body) > 0 {
+				outbound = With(outbound, "response_body", captureBody(body, string(c.Response().Header.ContentType()), cfg.MaxBodySize, redact))
+			}
+			l.InfoWithAction(outbound, OUTBOUND, c.Method()+" "+c.Path())
+		}
+		return nil
+	}
+}
+
+// RequestID returns the transaction id Middleware assigned to the request.
+func RequestID(c *fiber.Ctx) string {
+	id, _ := c.Locals(requestIDLocal).(string)
+	return id
+}
+
+func (cfg MiddlewareConfig) withClaims(c *fiber.Ctx, ctx context.Context) context.Context {
+	if cfg.Claims == nil {
+		return ctx
+	}
+	return With(ctx, cfg.Claims(c))
+}
+
+// captureBody returns the loggable form of body: JSON and form bodies with
+// the redact keys hidden, the size of anything else.
+func captureBody(body []byte, contentType string, limit int, redact map[string]bool) any {
+	if len(body) > limit {
+		return fmt.Sprintf("[%d bytes, over the %d byte limit]", len(body), limit)
+	}
+
+	mediaType, _, _ := strings.Cut(contentType, ";")
+	switch strings.TrimSpace(strings.ToLower(mediaType)) {
+	case fiber.MIMEApplicationJSON:
+		var value any
+		if err := json.Unmarshal(body, &value); err != nil {
+			return fmt.Sprintf("[%d bytes, invalid json]", len(body))
+		}
+		return redactValue(value, redact)
+	case fiber.MIMEApplicationForm:
+		values, err := url.ParseQuery(string(body))
+		if err != nil {
+			return fmt.Sprintf("[%d bytes, invalid form]", len(body))
+		}
+		form := make(map[string]any, len(values))
+		for k, v := range values {
+			if redact[strings.ToLower(k)] {
+				form[k] = "[REDACTED]"
+			} else if len(v) == 1 {
+				form[k] = v[0]
+			} else {
+				form[k] = v
+			}
+		}
+		return form
+	default:
+		return fmt.Sprintf("[%d bytes]", len(body))
+	}
+}
+
+func redactValue(value any, redact map[string]bool) any {
+	switch v := value.(type) {
+	case map[string]any:
+		for k, nested := range v {
+			if redact[strings.ToLower(k)] {
+				v[k] = "[REDACTED]"
+			} else {
+				v[k] = redactValue(nested, redact)
+			}
+		}
+	case []any:
+		for i, nested := range v {
+			v[i] = redactValue(nested, redact)
+		}
+	}
+	return value
+}
@@ -0,0 +1,177 @@
+package logger
+
+import (
+	"context"
+	"encoding/json"
+	"reflect"
+)
+
+type fieldsContextKey struct{}
+
+// layeredFields is stored in the context by With. Structs are converted once
+// there, merged also caches the LogHeader value of the context converted and
+// overlaid with own, valid as long as the LogHeader value is header.
+type layeredFields struct {
+	own    map[string]any
+	header any
+	merged map[string]any
+}
+
+// badKey holds a trailing key without value, as slog does.
+const badKey = "!BADKEY"
+
+// With returns a copy of ctx whose logs carry fields on top of those already
+// added by outer layers, a later value wins for the same key. fields are key
+// value pairs, a map or struct in key position adds all its JSON fields.
+//
+//	ctx = logger.With(ctx, "order_id", order.ID, "customer_id", order.CustomerID)
+//	ctx = logger.With(ctx, claims)
+func With(ctx context.Context, fields ...any) context.Context {
+	if len(fields) == 0 {
+		return ctx
+	}
+
+	var parent map[string]any
+	if p, ok := ctx.Value(fieldsContextKey{}).(*layeredFields); ok {
+		parent = p.own
+	}
+	own := make(map[string]any, len(parent)+len(fields)/2)
+	for k, v := range parent {
+		own[k] = v
+	}
+	addFields(own, fields)
+
+	// The maps are never written again, layers below keep their own.
+	layered := &layeredFields{own: own, merged: own}
+	if header := ctx.Value(LogHeader); header != nil && cacheable(header) {
+		layered.header = header
+		layered.merged = overlayHeader(header, own)
+	}
+	return context.WithValue(ctx, fieldsContextKey{}, layered)
+}
+
+// addFields stores fields, in the forms accepted by With, in dst.
+func addFields(dst map[string]any, fields []any) {
+	for i := 0; i < len(fields); i++ {
+		switch key := fields[i].(type) {
+		case string:
+			if i+1 == len(fields) {
+				dst[badKey] = key
+				continue
+			}
+			i++
+			dst[key] = fields[i]
+		case map[string]any:
+			for k, v := range key {
+				dst[k] = v
+			}
+		case nil:
+		default:
+			for k, v := range structToJson(key) {
+				dst[k] = v
+			}
+		}
+	}
+}
+
+// bindFields returns the bound fields of a child logger, parent stays as is.
+func bindFields(parent map[string]any, fields []any) map[string]any {
+	bound := make(map[string]any, len(parent)+len(fields)/2)
+	for k, v := range parent {
+		bound[k] = v
+	}
+	addFields(bound, fields)
+	return bound
+}
+
+// FieldsFrom returns the fields logged with ctx: the LogHeader value, if any,
+// overlaid with everything added by With. The map is a copy.
+func FieldsFrom(ctx context.Context) map[string]any {
+	fields := logFields(ctx)
+	copied := make(map[string]any, len(fields))
+	for k, v := range fields {
+		copied[k] = v
+	}
+	return copied
+}
+
+// entryFields merges the bound fields of a logger, the fields of ctx and
+// action into the fields of one entry, later ones winning, so no key is
+// written twice. bound is returned as is when there is nothing to merge.
+func entryFields(bound map[string]any, ctx context.Context, action string) map[string]any {
+	fields := logFields(ctx)
+	if len(fields) == 0 && action == "" {
+		return bound
+	}
+
+	merged := make(map[string]any, len(bound)+len(fields)+1)
+	for k, v := range bound {
+		merged[k] = v
+	}
+	for k, v := range fields {
+		merged[k] = v
+	}
+	if action != "" {
+		merged["action"] = action
+	}
+	return merged
+}
+
+var noFields = map[string]any{}
+
+// logFields is FieldsFrom for the loggers of this package, the map must not
+// be modified.
+func logFields(ctx context.Context) map[string]any {
+	layered, _ := ctx.Value(fieldsContextKey{}).(*layeredFields)
+	header := ctx.Value(LogHeader)
+
+	switch {
+	case layered == nil && header == nil:
+		return noFields
+	case layered == nil:
+		return overlayHeader(header, nil)
+	case header == nil:
+		return layered.own
+	case layered.header != nil && sameHeader(layered.header, header):
+		return layered.merged
+	default:
+		return overlayHeader(header, layered.own)
+	}
+}
+
+// overlayHeader converts the LogHeader value header and overlays fields.
+func overlayHeader(header any, fields map[string]any) map[string]any {
+	var merged map[string]any
+	if m, ok := header.(map[string]any); ok {
+		merged = make(map[string]any, len(m)+len(fields))
+		for k, v := range m {
+			merged[k] = v
+		}
+	} else if merged = structToJson(header); merged == nil {
+		merged = make(map[string]any, len(fields))
+	}
+	for k, v := range fields {
+		merged[k] = v
+	}
+	return merged
+}
+
+// cacheable reports whether a LogHeader value converted by With can be reused
+// while the context holds an equal value. Pointers and maps are not, their
+// content may change after With.
+func cacheable(header any) bool {
+	v := reflect.ValueOf(header)
+	return v.Kind() != reflect.Pointer && v.Comparable()
+}
+
+func sameHeader(cached, header any) bool {
+	return reflect.TypeOf(cached) == reflect.TypeOf(header) && cacheable(header) && cached == header
+}
+
+// convert any struct to map.
+func structToJson(st any) map[string]any {
+	data, _ := json.Marshal(st)
+	var kv map[string]any
+	json.Unmarshal(data, &kv)
+	return kv
+}
@@ -0,0 +1,106 @@
+package logger
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"reflect"
+	"testing"
+)
+
+// backends returns every logger of this package writing JSON into w.
+func backends(w io.Writer) map[string]ExtendedLogger {
+	return map[string]ExtendedLogger{
+		"logrus":  NewLogrus(WithOutput(w)),
+		"zerolog": NewZerolog(WithOutput(w)),
+		"zap":     NewZap(WithOutput(w)),
+		"slog":    NewSlog(slog.NewJSONHandler(w, &slog.HandlerOptions{Level: LevelTrace, ReplaceAttr: SlogReplaceAttr})),
+	}
+}
+
+// topLevelKeys lists the keys of a JSON object in order, duplicates kept,
+// which json.Unmarshal would silently merge.
+func topLevelKeys(t *testing.T, line []byte) []string {
+	t.Helper()
+	dec := json.NewDecoder(bytes.NewReader(line))
+	if tok, err := dec.Token(); err != nil || tok != json.Delim('{') {
+		t.Fatalf("not a JSON object: %s", line)
+	}
+
+	var keys []string
+	for dec.More() {
+		tok, err := dec.Token()
+		if err != nil {
+			t.Fatal(err)
+		}
+		keys = append(keys, tok.(string))
+		var value json.RawMessage
+		if err := dec.Decode(&value); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return keys
+}
+
+func TestContextFieldsOverrideBoundFields(t *testing.T) {
+	var buf bytes.Buffer
+	for name, l := range backends(&buf) {
+		t.Run(name, func(t *testing.T) {
+			buf.Reset()
+			ctx := With(context.Background(), "app", "override", "order_id", 7)
+			l.With("app", "bound", "tenant", "acme").InfoWithAction(ctx, OUTBOUND, "order created")
+
+			line := bytes.TrimSpace(buf.Bytes())
+			seen := map[string]bool{}
+			for _, key := range topLevelKeys(t, line) {
+				if seen[key] {
+					t.Fatalf("key %q written twice: %s", key, line)
+				}
+				seen[key] = true
+			}
+
+			var entry map[string]any
+			if err := json.Unmarshal(line, &entry); err != nil {
+				t.Fatal(err)
+			}
+			want := map[string]any{"app": "override", "tenant": "acme", "order_id": float64(7), "action": "OUTBOUND"}
+			for k, v := range want {
+				if entry[k] != v {
+					t.Errorf("%s = %v, want %v", k, entry[k], v)
+				}
+			}
+		})
+	}
+}
+
+func TestLogHeaderCachedByWith(t *testing.T) {
+	type header struct {
+		Path string `json:"path"`
+	}
+	ctx := context.WithValue(context.Background(), LogHeader, header{Path: "/orders"})
+	ctx = With(ctx, "order_id", 7)
+
+	fields := logFields(ctx)
+	if fields["path"] != "/orders" || fields["order_id"] != 7 {
+		t.Fatalf("fields = %v", fields)
+	}
+	if reflect.ValueOf(logFields(ctx)).Pointer() != reflect.ValueOf(fields).Pointer() {
+		t.Fatal("LogHeader converted again for the same value")
+	}
+
+	// A new LogHeader value below the layer is converted, not the cached one.
+	ctx = context.WithValue(ctx, LogHeader, header{Path: "/payments"})
+	if fields := logFields(ctx); fields["path"] != "/payments" || fields["order_id"] != 7 {
+		t.Fatalf("fields = %v", fields)
+	}
+
+	// Pointers may change after With, so they are never cached.
+	h := &header{Path: "/orders"}
+	ctx = With(context.WithValue(context.Background(), LogHeader, h), "order_id", 7)
+	h.Path = "/refunds"
+	if fields := logFields(ctx); fields["path"] != "/refunds" {
+		t.Fatalf("fields = %v", fields)
+	}
+}
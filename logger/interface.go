@@ -38,6 +38,41 @@ type EpicLogger interface {
 	InfoWithAction(ctx context.Context, action LogAction, msg string, data ...any)
 }
 
+// ExtendedLogger adds Debug, Fatal and child loggers to EpicLogger. It is kept
+// apart so existing EpicLogger implementations still compile, the loggers of
+// this package implement it.
+//
+//	if l, ok := logger.Logger.(logger.ExtendedLogger); ok {
+//		l.Named("billing").Debug(ctx, "invoice computed")
+//	}
+type ExtendedLogger interface {
+	EpicLogger
+	Debug(ctx context.Context, msg string, data ...any)
+	// Fatal logs then exits the process with status 1.
+	Fatal(ctx context.Context, msg string, data ...any)
+	// Audit logs at WarnLevel whatever the levels, for entries that must not
+	// be lost such as level changes.
+	Audit(ctx context.Context, msg string, data ...any)
+
+	// With returns a child logger adding fields to every entry, in the forms
+	// accepted by the package level With.
+	With(fields ...any) ExtendedLogger
+	// Named returns a child logger for component, logged as "component".
+	// Nested names are joined with dots.
+	Named(component string) ExtendedLogger
+
+	// Levels controls the level of the logger and its children at runtime.
+	Levels() *Levels
+}
+
+// childName joins the component of a parent logger with the one of its child.
+func childName(parent, component string) string {
+	if parent == "" {
+		return component
+	}
+	return parent + "." + component
+}
+
 var (
 	Logger EpicLogger
 	once   sync.Once
@@ -0,0 +1,150 @@
+package logger
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"sync/atomic"
+)
+
+type Level int32
+
+const (
+	TraceLevel Level = iota
+	DebugLevel
+	InfoLevel
+	WarnLevel
+	ErrorLevel
+	FatalLevel
+)
+
+// Names of the "level" field, as logrus writes them.
+var levelNames = [...]string{
+	"trace",
+	"debug",
+	"info",
+	"warning",
+	"error",
+	"fatal",
+}
+
+func (l Level) String() string {
+	if l < TraceLevel || l > FatalLevel {
+		return fmt.Sprintf("Level(%d)", l)
+	}
+	return levelNames[l]
+}
+
+// ParseLevel parses a level name, case-insensitively. "warn" is accepted for
+// "warning".
+func ParseLevel(name string) (Level, error) {
+	name = strings.ToLower(strings.TrimSpace(name))
+	if name == "warn" {
+		return WarnLevel, nil
+	}
+	for i, levelName := range levelNames {
+		if name == levelName {
+			return Level(i), nil
+		}
+	}
+	return 0, fmt.Errorf("unknown log level %q", name)
+}
+
+func (l Level) MarshalText() ([]byte, error) {
+	return []byte(l.String()), nil
+}
+
+func (l *Level) UnmarshalText(text []byte) error {
+	level, err := ParseLevel(string(text))
+	if err != nil {
+		return err
+	}
+	*l = level
+	return nil
+}
+
+// Levels is the level of a logger and its children, changeable at runtime.
+// Components, see ExtendedLogger.Named, may override it: "db" applies to
+// "db.gorm" unless "db.gorm" has its own.
+type Levels struct {
+	level atomic.Int32
+
+	mu        sync.Mutex // serializes writers of overrides
+	overrides atomic.Pointer[map[string]Level]
+}
+
+func NewLevels(level Level) *Levels {
+	levels := &Levels{}
+	levels.level.Store(int32(level))
+	return levels
+}
+
+// Level returns the level of loggers without override.
+func (l *Levels) Level() Level {
+	return Level(l.level.Load())
+}
+
+func (l *Levels) SetLevel(level Level) {
+	l.level.Store(int32(level))
+}
+
+// For returns the level applied to component.
+func (l *Levels) For(component string) Level {
+	if overrides := l.overrides.Load(); overrides != nil && component != "" {
+		for name := component; ; {
+			if level, ok := (*overrides)[name]; ok {
+				return level
+			}
+			i := strings.LastIndexByte(name, '.')
+			if i < 0 {
+				break
+			}
+			name = name[:i]
+		}
+	}
+	return l.Level()
+}
+
+// Enabled reports whether entries of level are logged for component.
+func (l *Levels) Enabled(component string, level Level) bool {
+	return level >= l.For(component)
+}
+
+// Overrides returns a copy of the component levels.
+func (l *Levels) Overrides() map[string]Level {
+	overrides := map[string]Level{}
+	if current := l.overrides.Load(); current != nil {
+		for k, v := range *current {
+			overrides[k] = v
+		}
+	}
+	return overrides
+}
+
+func (l *Levels) SetOverride(component string, level Level) {
+	l.updateOverrides(func(overrides map[string]Level) {
+		overrides[component] = level
+	})
+}
+
+// ClearOverride makes component follow the level of its parent again.
+func (l *Levels) ClearOverride(component string) {
+	l.updateOverrides(func(overrides map[string]Level) {
+		delete(overrides, component)
+	})
+}
+
+// updateOverrides swaps in an updated copy, loggers read the map without
+// locking.
+func (l *Levels) updateOverrides(update func(map[string]Level)) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	overrides := l.Overrides()
+	update(overrides)
+	if len(overrides) == 0 {
+		l.overrides.Store(nil)
+		return
+	}
+	l.overrides.Store(&overrides)
+}
@@ -2,106 +2,35 @@ package logger
 
 import (
 	"context"
-	"encoding/json"
 	"fmt"
-	"io"
-	"os"
-	"time"
 
 	"github.com/sirupsen/logrus"
-	"gopkg.in/natefinch/lumberjack.v2"
-)
-
-type RotationType int
-
-const (
-	Date      RotationType = iota // default "yyyy-mm-dd"
-	Timestamp                     // "yyyy-mm-ddT-hh-mm-ss"
 )
 
 type EpicLogrus struct {
-	logger     *logrus.Logger
-	rotation   RotationType
-	maxSize    int // work only with rotationType set to Timestamp
-	maxBackups int // work only with rotationType set to Timestamp
-	appName    string
-	path       string // default root running
+	options
+	logger    *logrus.Logger
+	fields    logrus.Fields // bound by With and Named, logged under the context fields
+	component string
 	// infoFunc   func() // user can customize logging behavior
 	// errFunc    func()
 }
 
-// ** Support configuration via "functional options pattern"
-type LogrusOption func(*EpicLogrus)
-
-func WithMaxSize(maxSize int) LogrusOption {
-	return func(le *EpicLogrus) {
-		le.maxSize = maxSize
-	}
-}
-
-func WithMaxBackups(maxBackups int) LogrusOption {
-	return func(le *EpicLogrus) {
-		le.maxBackups = maxBackups
-	}
-}
-
-func WithRotationType(rotationType RotationType) LogrusOption {
-	return func(le *EpicLogrus) {
-		le.rotation = rotationType
-	}
-}
-
-func WithAppName(name string) LogrusOption {
-	return func(le *EpicLogrus) {
-		le.appName = name
-	}
-}
-
-func WithPath(path string) LogrusOption {
-	return func(le *EpicLogrus) {
-		le.path = path
-	}
-}
-
 func NewLogrus(options ...LogrusOption) *EpicLogrus {
 
 	// Default configuration.
-	client := EpicLogrus{
-		rotation:   Date,
-		maxSize:    500,
-		maxBackups: 3,
-		appName:    "epic-app",
-	}
+	client := EpicLogrus{options: defaultOptions()}
 
 	// Apply custom logrus configuration
 	for _, option := range options {
-		option(&client)
+		option(&client.options)
 	}
 
+	client.runtimeLevels()
 	client.logger = logrus.New()
-
-	if client.rotation == Timestamp {
-		client.logger.SetOutput(&lumberjack.Logger{
-			Filename:   fmt.Sprintf("logs/%s.log", client.appName),
-			MaxSize:    client.maxSize, // megabytes
-			MaxBackups: client.maxBackups,
-			MaxAge:     28, //days
-			Compress:   true,
-		})
-	} else {
-		// Create parent logs dir
-		if _, err := os.Stat("logs"); os.IsNotExist(err) {
-			err := os.Mkdir("logs", 0770)
-			panic(err)
-		}
-
-		date := time.Now().UTC()
-		logFile, err := os.OpenFile("logs/log_"+date.Format("01-02-2006_15")+".log", os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0666)
-		if err != nil {
-			panic(err)
-		}
-		client.logger.SetOutput(io.MultiWriter(os.Stdout, logFile))
-	}
+	client.logger.SetOutput(client.writer())
+	// Levels filters, logrus lets everything through.
+	client.logger.SetLevel(logrus.TraceLevel)
 
 	// Log detail configuration.
 	client.logger.SetFormatter(&logrus.JSONFormatter{
@@ -110,46 +39,116 @@ func NewLogrus(options ...LogrusOption) *EpicLogrus {
 			logrus.FieldKeyMsg:  "message",
 		},
 	})
-	client.logger.WithFields(logrus.Fields{
+	client.fields = logrus.Fields{
 		"app": client.appName,
-	})
+	}
+	client.reportLevelErr(&client)
 
 	return &client
 }
 
 func (l *EpicLogrus) Info(ctx context.Context, msg string, data ...any) {
-	// Process context to be log as metadata
-	fields := structToJson(ctx.Value(LogHeader))
-
-	l.logger.WithFields(fields).Info(append([]any{msg}, data...)...)
+	l.log(ctx, InfoLevel, "", msg, data)
 }
 
 func (l *EpicLogrus) Error(ctx context.Context, msg string, data ...any) {
-	fields := structToJson(ctx.Value(LogHeader))
-	l.logger.WithFields(fields).Error(append([]any{msg}, data...)...)
+	l.log(ctx, ErrorLevel, "", msg, data)
 }
 
 func (l *EpicLogrus) Warn(ctx context.Context, msg string, data ...any) {
-	fields := structToJson(ctx.Value(LogHeader))
-	l.logger.WithFields(fields).Warn(append([]any{msg}, data...)...)
+	l.log(ctx, WarnLevel, "", msg, data)
+}
+
+func (l *EpicLogrus) Debug(ctx context.Context, msg string, data ...any) {
+	l.log(ctx, DebugLevel, "", msg, data)
 }
 
 func (l *EpicLogrus) Trace(ctx context.Context, msg string, data ...any) {
-	fields := structToJson(ctx.Value(LogHeader))
-	l.logger.WithFields(fields).Trace(append([]any{msg}, data...)...)
+	l.log(ctx, TraceLevel, "", msg, data)
+}
+
+// Fatal exits even when FatalLevel is filtered out.
+func (l *EpicLogrus) Fatal(ctx context.Context, msg string, data ...any) {
+	l.log(ctx, FatalLevel, "", msg, data)
+	l.logger.Exit(1)
+}
+
+func (l *EpicLogrus) Audit(ctx context.Context, msg string, data ...any) {
+	l.write(ctx, WarnLevel, "", msg, data)
 }
 
 func (l *EpicLogrus) InfoWithAction(ctx context.Context, action LogAction, msg string, data ...any) {
-	actionName := logActionName[action]
-	fields := structToJson(ctx.Value(LogHeader))
-	fields["action"] = actionName
-	l.logger.WithFields(fields).Info(append([]any{msg}, data...)...)
-}
-
-// convert any struct to map.
-func structToJson(st any) map[string]any {
-	data, _ := json.Marshal(st)
-	var kv map[string]any
-	json.Unmarshal(data, &kv)
-	return kv
+	l.log(ctx, InfoLevel, logActionName[action], msg, data)
+}
+
+// Levels controls the level of l and its children at runtime.
+func (l *EpicLogrus) Levels() *Levels {
+	return l.levels
+}
+
+func (l *EpicLogrus) With(fields ...any) ExtendedLogger {
+	child := *l
+	child.fields = bindFields(l.fields, fields)
+	return &child
+}
+
+func (l *EpicLogrus) Named(component string) ExtendedLogger {
+	name := childName(l.component, component)
+	child := l.With("component", name).(*EpicLogrus)
+	child.component = name
+	return child
+}
+
+// logrusLevels maps Level to logrus, whose levels go the other way round.
+var logrusLevels = [...]logrus.Level{
+	TraceLevel: logrus.TraceLevel,
+	DebugLevel: logrus.DebugLevel,
+	InfoLevel:  logrus.InfoLevel,
+	WarnLevel:  logrus.WarnLevel,
+	ErrorLevel: logrus.ErrorLevel,
+	FatalLevel: logrus.FatalLevel,
+}
+
+func (l *EpicLogrus) log(ctx context.Context, level Level, action string, msg string, data []any) {
+	if !l.levels.Enabled(l.component, level) {
+		return
+	}
+	l.write(ctx, level, action, msg, data)
+}
+
+// write logs without checking the levels.
+func (l *EpicLogrus) write(ctx context.Context, level Level, action string, msg string, data []any) {
+	entry := l.entry(ctx)
+	if action != "" {
+		entry.Data["action"] = action
+	}
+	// Entry.Log never exits, Fatal does that after logging.
+	entry.Log(logrusLevels[level], append([]any{msg}, data...)...)
+}
+
+// entry merges the bound fields with the context fields, building the data
+// map once instead of letting logrus copy it per WithFields call.
+func (l *EpicLogrus) entry(ctx context.Context) *logrus.Entry {
+	// Process context to be log as metadata
+	fields := logFields(ctx)
+
+	data := make(logrus.Fields, len(l.fields)+len(fields)+1)
+	for k, v := range l.fields {
+		data[k] = v
+	}
+	for k, v := range fields {
+		data[k] = v
+	}
+
+	entry := logrus.NewEntry(l.logger)
+	entry.Data = data
+	return entry
+}
+
+// formatMessage joins msg and data the way logrus does, with fmt.Sprint.
+func formatMessage(msg string, data []any) string {
+	if len(data) == 0 {
+		return msg
+	}
+	return fmt.Sprint(append([]any{msg}, data...)...)
 }
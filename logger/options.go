@@ -0,0 +1,161 @@
+package logger
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"time"
+
+	"gopkg.in/natefinch/lumberjack.v2"
+)
+
+type RotationType int
+
+const (
+	Date      RotationType = iota // default "yyyy-mm-dd"
+	Timestamp                     // "yyyy-mm-ddT-hh-mm-ss"
+)
+
+// options are shared by every EpicLogger implementation of this package.
+type options struct {
+	rotation   RotationType
+	maxSize    int // work only with rotationType set to Timestamp
+	maxBackups int // work only with rotationType set to Timestamp
+	appName    string
+	path       string // default root running
+	output     io.Writer
+	level      Level
+	levels     *Levels
+	levelErr   error // invalid LevelEnv, reported once the logger is built
+}
+
+// ** Support configuration via "functional options pattern"
+type Option func(*options)
+
+// LogrusOption is kept for code written before the options were shared.
+type LogrusOption = Option
+
+// LevelEnv names the environment variable holding the default level.
+const LevelEnv = "LOG_LEVEL"
+
+func defaultOptions() options {
+	o := options{
+		rotation:   Date,
+		maxSize:    500,
+		maxBackups: 3,
+		appName:    "epic-app",
+		level:      InfoLevel,
+	}
+	if name := os.Getenv(LevelEnv); name != "" {
+		level, err := ParseLevel(name)
+		if err != nil {
+			o.levelErr = fmt.Errorf("%s: %w, using %s", LevelEnv, err, o.level)
+		} else {
+			o.level = level
+		}
+	}
+	return o
+}
+
+// reportLevelErr logs the LevelEnv error of defaultOptions through l.
+func (o *options) reportLevelErr(l EpicLogger) {
+	if o.levelErr != nil {
+		l.Error(context.Background(), o.levelErr.Error())
+	}
+}
+
+// runtimeLevels returns the Levels of the logger being built.
+func (o *options) runtimeLevels() *Levels {
+	if o.levels == nil {
+		o.levels = NewLevels(o.level)
+	}
+	return o.levels
+}
+
+func WithMaxSize(maxSize int) Option {
+	return func(o *options) {
+		o.maxSize = maxSize
+	}
+}
+
+func WithMaxBackups(maxBackups int) Option {
+	return func(o *options) {
+		o.maxBackups = maxBackups
+	}
+}
+
+func WithRotationType(rotationType RotationType) Option {
+	return func(o *options) {
+		o.rotation = rotationType
+	}
+}
+
+func WithAppName(name string) Option {
+	return func(o *options) {
+		o.appName = name
+	}
+}
+
+func WithPath(path string) Option {
+	return func(o *options) {
+		o.path = path
+	}
+}
+
+// WithLevel sets the starting level, default InfoLevel or the one in the
+// LOG_LEVEL environment variable, an invalid one is logged and ignored.
+func WithLevel(level Level) Option {
+	return func(o *options) {
+		o.level = level
+		o.levelErr = nil
+	}
+}
+
+// WithLevels shares levels between loggers, so one admin call changes all.
+// WithLevel is then ignored.
+func WithLevels(levels *Levels) Option {
+	return func(o *options) {
+		o.levels = levels
+		o.levelErr = nil
+	}
+}
+
+// WithOutput writes logs to w instead of the log files, e.g. os.Stdout in
+// containers.
+func WithOutput(w io.Writer) Option {
+	return func(o *options) {
+		o.output = w
+	}
+}
+
+// writer opens the log destination described by o.
+func (o *options) writer() io.Writer {
+	if o.output != nil {
+		return o.output
+	}
+
+	dir := filepath.Join(o.path, "logs")
+	if o.rotation == Timestamp {
+		return &lumberjack.Logger{
+			Filename:   filepath.Join(dir, fmt.Sprintf("%s.log", o.appName)),
+			MaxSize:    o.maxSize, // megabytes
+			MaxBackups: o.maxBackups,
+			MaxAge:     28, //days
+			Compress:   true,
+		}
+	}
+
+	// Create parent logs dir
+	if err := os.MkdirAll(dir, 0770); err != nil {
+		panic(err)
+	}
+
+	date := time.Now().UTC()
+	logFile, err := os.OpenFile(filepath.Join(dir, "log_"+date.Format("01-02-2006_15")+".log"), os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0666)
+	if err != nil {
+		panic(err)
+	}
+	return io.MultiWriter(os.Stdout, logFile)
+}
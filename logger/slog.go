@@ -0,0 +1,304 @@
+package logger
+
+import (
+	"context"
+	"log/slog"
+	"os"
+	"slices"
+	"sort"
+	"time"
+)
+
+// Levels of EpicLogger.Trace and ExtendedLogger.Fatal, which slog lacks.
+const (
+	LevelTrace = slog.LevelDebug - 4
+	LevelFatal = slog.LevelError + 4
+)
+
+// EpicSlog is an EpicLogger writing through a slog.Handler, with the context
+// fields (see FieldsFrom) added as attributes.
+type EpicSlog struct {
+	handler   slog.Handler
+	fields    map[string]any // added with the context fields to every record
+	component string
+	levels    *Levels
+}
+
+// NewSlog logs through handler. Use SlogReplaceAttr in its options to get
+// the field names of EpicLogrus. Only the level options apply, entries they
+// let through still go through the level of handler.
+//
+//	logger.NewSlog(slog.NewJSONHandler(os.Stdout, &slog.HandlerOptions{
+//		Level:       logger.LevelTrace,
+//		ReplaceAttr: logger.SlogReplaceAttr,
+//	}))
+func NewSlog(handler slog.Handler, options ...Option) *EpicSlog {
+	o := defaultOptions()
+	for _, option := range options {
+		option(&o)
+	}
+	l := &EpicSlog{handler: handler, levels: o.runtimeLevels()}
+	o.reportLevelErr(l)
+	return l
+}
+
+func (l *EpicSlog) Info(ctx context.Context, msg string, data ...any) {
+	l.log(ctx, InfoLevel, "", msg, data)
+}
+
+func (l *EpicSlog) Error(ctx context.Context, msg string, data ...any) {
+	l.log(ctx, ErrorLevel, "", msg, data)
+}
+
+func (l *EpicSlog) Warn(ctx context.Context, msg string, data ...any) {
+	l.log(ctx, WarnLevel, "", msg, data)
+}
+
+func (l *EpicSlog) Trace(ctx context.Context, msg string, data ...any) {
+	l.log(ctx, TraceLevel, "", msg, data)
+}
+
+func (l *EpicSlog) Debug(ctx context.Context, msg string, data ...any) {
+	l.log(ctx, DebugLevel, "", msg, data)
+}
+
+// Fatal exits even when FatalLevel is filtered out.
+func (l *EpicSlog) Fatal(ctx context.Context, msg string, data ...any) {
+	l.log(ctx, FatalLevel, "", msg, data)
+	os.Exit(1)
+}
+
+// Audit still goes through the level of the handler.
+func (l *EpicSlog) Audit(ctx context.Context, msg string, data ...any) {
+	l.write(ctx, WarnLevel, "", msg, data)
+}
+
+func (l *EpicSlog) InfoWithAction(ctx context.Context, action LogAction, msg string, data ...any) {
+	l.log(ctx, InfoLevel, logActionName[action], msg, data)
+}
+
+// Levels controls the level of l and its children at runtime.
+func (l *EpicSlog) Levels() *Levels {
+	return l.levels
+}
+
+func (l *EpicSlog) With(fields ...any) ExtendedLogger {
+	child := *l
+	child.fields = bindFields(l.fields, fields)
+	return &child
+}
+
+func (l *EpicSlog) Named(component string) ExtendedLogger {
+	name := childName(l.component, component)
+	child := l.With("component", name).(*EpicSlog)
+	child.component = name
+	return child
+}
+
+var slogLevels = [...]slog.Level{
+	TraceLevel: LevelTrace,
+	DebugLevel: slog.LevelDebug,
+	InfoLevel:  slog.LevelInfo,
+	WarnLevel:  slog.LevelWarn,
+	ErrorLevel: slog.LevelError,
+	FatalLevel: LevelFatal,
+}
+
+func (l *EpicSlog) log(ctx context.Context, level Level, action string, msg string, data []any) {
+	if !l.levels.Enabled(l.component, level) {
+		return
+	}
+	l.write(ctx, level, action, msg, data)
+}
+
+// write logs without checking the levels. The bound and context fields are
+// merged first, so a key is never added twice.
+func (l *EpicSlog) write(ctx context.Context, level Level, action string, msg string, data []any) {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	if !l.handler.Enabled(ctx, slogLevels[level]) {
+		return
+	}
+
+	record := slog.NewRecord(time.Now(), slogLevels[level], formatMessage(msg, data), 0)
+
+	record.AddAttrs(sortedAttrs(entryFields(l.fields, ctx, action))...)
+
+	l.handler.Handle(ctx, record)
+}
+
+func sortedAttrs(fields map[string]any) []slog.Attr {
+	keys := make([]string, 0, len(fields))
+	for k := range fields {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	attrs := make([]slog.Attr, 0, len(keys))
+	for _, k := range keys {
+		attrs = append(attrs, slog.Any(k, fields[k]))
+	}
+	return attrs
+}
+
+// SlogReplaceAttr renames the slog built-in keys to the EpicLogrus schema:
+// "@timestamp", "message" and a lower case "level".
+func SlogReplaceAttr(groups []string, a slog.Attr) slog.Attr {
+	if len(groups) > 0 {
+		return a
+	}
+	switch a.Key {
+	case slog.TimeKey:
+		a.Key = "@timestamp"
+	case slog.MessageKey:
+		a.Key = "message"
+	case slog.LevelKey:
+		if level, ok := a.Value.Any().(slog.Level); ok {
+			a.Value = slog.StringValue(levelName(level))
+		}
+	}
+	return a
+}
+
+func levelName(level slog.Level) string {
+	switch {
+	case level < slog.LevelDebug:
+		return "trace"
+	case level < slog.LevelInfo:
+		return "debug"
+	case level < slog.LevelWarn:
+		return "info"
+	case level < slog.LevelError:
+		return "warning"
+	case level < LevelFatal:
+		return "error"
+	default:
+		return "fatal"
+	}
+}
+
+// SlogHandler is a slog.Handler forwarding records into an EpicLogger, so
+// libraries logging through slog end up in the same pipeline. Attributes are
+// added to the record context fields with With.
+type SlogHandler struct {
+	logger EpicLogger
+	level  slog.Leveler
+	attrs  map[string]any
+	groups []string
+}
+
+// NewSlogHandler forwards records of at least level into l, nil level
+// forwards everything.
+//
+//	slog.SetDefault(slog.New(logger.NewSlogHandler(logger.Logger, slog.LevelInfo)))
+func NewSlogHandler(l EpicLogger, level slog.Leveler) *SlogHandler {
+	if level == nil {
+		level = LevelTrace
+	}
+	return &SlogHandler{logger: l, level: level, attrs: map[string]any{}}
+}
+
+func (h *SlogHandler) Enabled(_ context.Context, level slog.Level) bool {
+	return level >= h.level.Level()
+}
+
+func (h *SlogHandler) Handle(ctx context.Context, record slog.Record) error {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	fields := map[string]any{}
+	mergeFields(fields, h.attrs)
+	record.Attrs(func(a slog.Attr) bool {
+		addAttr(fields, h.groups, a)
+		return true
+	})
+	ctx = With(ctx, fields)
+
+	extended, _ := h.logger.(ExtendedLogger)
+	switch {
+	case record.Level < slog.LevelDebug || record.Level < slog.LevelInfo && extended == nil:
+		h.logger.Trace(ctx, record.Message)
+	case record.Level < slog.LevelInfo:
+		extended.Debug(ctx, record.Message)
+	case record.Level < slog.LevelWarn:
+		h.logger.Info(ctx, record.Message)
+	case record.Level < slog.LevelError:
+		h.logger.Warn(ctx, record.Message)
+	default:
+		h.logger.Error(ctx, record.Message)
+	}
+	return nil
+}
+
+func (h *SlogHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	next := h.clone()
+	for _, a := range attrs {
+		addAttr(next.attrs, next.groups, a)
+	}
+	return next
+}
+
+func (h *SlogHandler) WithGroup(name string) slog.Handler {
+	if name == "" {
+		return h
+	}
+	next := h.clone()
+	next.groups = append(slices.Clip(next.groups), name)
+	return next
+}
+
+func (h *SlogHandler) clone() *SlogHandler {
+	attrs := map[string]any{}
+	mergeFields(attrs, h.attrs)
+	return &SlogHandler{logger: h.logger, level: h.level, attrs: attrs, groups: h.groups}
+}
+
+// addAttr stores a in fields, nested under groups.
+func addAttr(fields map[string]any, groups []string, a slog.Attr) {
+	a.Value = a.Value.Resolve()
+	if a.Equal(slog.Attr{}) {
+		return
+	}
+
+	for _, group := range groups {
+		nested, ok := fields[group].(map[string]any)
+		if !ok {
+			nested = map[string]any{}
+			fields[group] = nested
+		}
+		fields = nested
+	}
+
+	if a.Value.Kind() != slog.KindGroup {
+		fields[a.Key] = a.Value.Any()
+		return
+	}
+	if a.Key == "" {
+		// Inline group.
+		for _, member := range a.Value.Group() {
+			addAttr(fields, nil, member)
+		}
+		return
+	}
+	for _, member := range a.Value.Group() {
+		addAttr(fields, []string{a.Key}, member)
+	}
+}
+
+// mergeFields deep copies src into dst.
+func mergeFields(dst, src map[string]any) {
+	for k, v := range src {
+		if nested, ok := v.(map[string]any); ok {
+			copied, ok := dst[k].(map[string]any)
+			if !ok {
+				copied = map[string]any{}
+			}
+			mergeFields(copied, nested)
+			dst[k] = copied
+			continue
+		}
+		dst[k] = v
+	}
+}
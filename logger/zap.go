@@ -0,0 +1,148 @@
+package logger
+
+import (
+	"context"
+	"os"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// zapTraceLevel sits below zap's lowest level, zap has no trace.
+const zapTraceLevel = zapcore.DebugLevel - 1
+
+// EpicZap is an EpicLogger backed by zap, writing the same fields as
+// EpicLogrus with far fewer allocations.
+type EpicZap struct {
+	options
+	logger    *zap.Logger
+	fields    map[string]any // written with the context fields of every entry
+	component string
+}
+
+func NewZap(options ...Option) *EpicZap {
+	client := EpicZap{options: defaultOptions()}
+	for _, option := range options {
+		option(&client.options)
+	}
+
+	encoder := zapcore.NewJSONEncoder(zapcore.EncoderConfig{
+		TimeKey:        "@timestamp",
+		LevelKey:       "level",
+		MessageKey:     "message",
+		EncodeTime:     zapcore.RFC3339TimeEncoder,
+		EncodeLevel:    encodeZapLevel,
+		EncodeDuration: zapcore.StringDurationEncoder,
+	})
+	client.runtimeLevels()
+	// Levels filters, zap lets everything through.
+	core := zapcore.NewCore(encoder, zapcore.AddSync(client.writer()), zap.LevelEnablerFunc(func(zapcore.Level) bool {
+		return true
+	}))
+	client.logger = zap.New(core)
+	client.fields = map[string]any{"app": client.appName}
+	client.reportLevelErr(&client)
+
+	return &client
+}
+
+func (l *EpicZap) Info(ctx context.Context, msg string, data ...any) {
+	l.log(ctx, InfoLevel, "", msg, data)
+}
+
+func (l *EpicZap) Error(ctx context.Context, msg string, data ...any) {
+	l.log(ctx, ErrorLevel, "", msg, data)
+}
+
+func (l *EpicZap) Warn(ctx context.Context, msg string, data ...any) {
+	l.log(ctx, WarnLevel, "", msg, data)
+}
+
+func (l *EpicZap) Trace(ctx context.Context, msg string, data ...any) {
+	l.log(ctx, TraceLevel, "", msg, data)
+}
+
+func (l *EpicZap) Debug(ctx context.Context, msg string, data ...any) {
+	l.log(ctx, DebugLevel, "", msg, data)
+}
+
+// Fatal exits through zap, after flushing the entry, or right away when
+// FatalLevel is filtered out.
+func (l *EpicZap) Fatal(ctx context.Context, msg string, data ...any) {
+	l.log(ctx, FatalLevel, "", msg, data)
+	os.Exit(1)
+}
+
+func (l *EpicZap) Audit(ctx context.Context, msg string, data ...any) {
+	l.write(ctx, WarnLevel, "", msg, data)
+}
+
+func (l *EpicZap) InfoWithAction(ctx context.Context, action LogAction, msg string, data ...any) {
+	l.log(ctx, InfoLevel, logActionName[action], msg, data)
+}
+
+// Levels controls the level of l and its children at runtime.
+func (l *EpicZap) Levels() *Levels {
+	return l.levels
+}
+
+func (l *EpicZap) With(fields ...any) ExtendedLogger {
+	child := *l
+	child.fields = bindFields(l.fields, fields)
+	return &child
+}
+
+func (l *EpicZap) Named(component string) ExtendedLogger {
+	name := childName(l.component, component)
+	child := l.With("component", name).(*EpicZap)
+	child.component = name
+	return child
+}
+
+var zapLevels = [...]zapcore.Level{
+	TraceLevel: zapTraceLevel,
+	DebugLevel: zapcore.DebugLevel,
+	InfoLevel:  zapcore.InfoLevel,
+	WarnLevel:  zapcore.WarnLevel,
+	ErrorLevel: zapcore.ErrorLevel,
+	FatalLevel: zapcore.FatalLevel,
+}
+
+func (l *EpicZap) log(ctx context.Context, level Level, action string, msg string, data []any) {
+	if !l.levels.Enabled(l.component, level) {
+		return
+	}
+	l.write(ctx, level, action, msg, data)
+}
+
+// write logs without checking the levels. The bound and context fields are
+// merged first, so a key is never written twice.
+func (l *EpicZap) write(ctx context.Context, level Level, action string, msg string, data []any) {
+	entry := l.logger.Check(zapLevels[level], "")
+	if entry == nil {
+		return
+	}
+
+	entry.Message = formatMessage(msg, data)
+	entry.Write(zapFields(entryFields(l.fields, ctx, action))...)
+}
+
+func zapFields(fields map[string]any) []zap.Field {
+	zapped := make([]zap.Field, 0, len(fields))
+	for k, v := range fields {
+		zapped = append(zapped, zap.Any(k, v))
+	}
+	return zapped
+}
+
+// encodeZapLevel writes the logrus level names.
+func encodeZapLevel(level zapcore.Level, enc zapcore.PrimitiveArrayEncoder) {
+	switch level {
+	case zapTraceLevel:
+		enc.AppendString("trace")
+	case zapcore.WarnLevel:
+		enc.AppendString("warning")
+	default:
+		zapcore.LowercaseLevelEncoder(level, enc)
+	}
+}
@@ -0,0 +1,108 @@
+package logger
+
+import (
+	"context"
+	"os"
+	"time"
+
+	"github.com/rs/zerolog"
+)
+
+// EpicZerolog is an EpicLogger backed by zerolog, writing the same fields as
+// EpicLogrus with far fewer allocations.
+type EpicZerolog struct {
+	options
+	logger    zerolog.Logger
+	fields    map[string]any // written with the context fields of every entry
+	component string
+}
+
+func NewZerolog(options ...Option) *EpicZerolog {
+	client := EpicZerolog{options: defaultOptions()}
+	for _, option := range options {
+		option(&client.options)
+	}
+
+	client.runtimeLevels()
+	// Levels filters, zerolog lets everything through.
+	client.logger = zerolog.New(client.writer()).Level(zerolog.TraceLevel)
+	client.fields = map[string]any{"app": client.appName}
+	client.reportLevelErr(&client)
+
+	return &client
+}
+
+func (l *EpicZerolog) Info(ctx context.Context, msg string, data ...any) {
+	l.event(ctx, InfoLevel).Str("message", formatMessage(msg, data)).Send()
+}
+
+func (l *EpicZerolog) Error(ctx context.Context, msg string, data ...any) {
+	l.event(ctx, ErrorLevel).Str("message", formatMessage(msg, data)).Send()
+}
+
+func (l *EpicZerolog) Warn(ctx context.Context, msg string, data ...any) {
+	l.event(ctx, WarnLevel).Str("message", formatMessage(msg, data)).Send()
+}
+
+func (l *EpicZerolog) Trace(ctx context.Context, msg string, data ...any) {
+	l.event(ctx, TraceLevel).Str("message", formatMessage(msg, data)).Send()
+}
+
+func (l *EpicZerolog) Debug(ctx context.Context, msg string, data ...any) {
+	l.event(ctx, DebugLevel).Str("message", formatMessage(msg, data)).Send()
+}
+
+// Fatal exits even when FatalLevel is filtered out.
+func (l *EpicZerolog) Fatal(ctx context.Context, msg string, data ...any) {
+	l.event(ctx, FatalLevel).Str("message", formatMessage(msg, data)).Send()
+	os.Exit(1)
+}
+
+func (l *EpicZerolog) Audit(ctx context.Context, msg string, data ...any) {
+	l.levelEvent(ctx, WarnLevel, "").Str("message", formatMessage(msg, data)).Send()
+}
+
+func (l *EpicZerolog) InfoWithAction(ctx context.Context, action LogAction, msg string, data ...any) {
+	l.actionEvent(ctx, InfoLevel, logActionName[action]).Str("message", formatMessage(msg, data)).Send()
+}
+
+// Levels controls the level of l and its children at runtime.
+func (l *EpicZerolog) Levels() *Levels {
+	return l.levels
+}
+
+func (l *EpicZerolog) With(fields ...any) ExtendedLogger {
+	child := *l
+	child.fields = bindFields(l.fields, fields)
+	return &child
+}
+
+func (l *EpicZerolog) Named(component string) ExtendedLogger {
+	name := childName(l.component, component)
+	child := l.With("component", name).(*EpicZerolog)
+	child.component = name
+	return child
+}
+
+// event starts an entry with the logrus field names, nil when level is
+// disabled. zerolog keeps its field names in package globals, so they are
+// written by hand instead of changed for every zerolog user.
+func (l *EpicZerolog) event(ctx context.Context, level Level) *zerolog.Event {
+	return l.actionEvent(ctx, level, "")
+}
+
+func (l *EpicZerolog) actionEvent(ctx context.Context, level Level, action string) *zerolog.Event {
+	if !l.levels.Enabled(l.component, level) {
+		return nil
+	}
+	return l.levelEvent(ctx, level, action)
+}
+
+// levelEvent is actionEvent without checking the levels. The bound and
+// context fields are merged first, so a key is never written twice.
+func (l *EpicZerolog) levelEvent(ctx context.Context, level Level, action string) *zerolog.Event {
+	return l.logger.Log().
+		Str("level", level.String()).
+		Time("@timestamp", time.Now()).
+		Fields(entryFields(l.fields, ctx, action))
+}
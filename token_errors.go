@@ -0,0 +1,40 @@
+package pkgep
+
+import (
+	"errors"
+
+	"github.com/epicconsult/pkgep/jwt"
+	"github.com/gofiber/fiber/v2"
+)
+
+// TokenErrorStatus maps an error returned while verifying a token to the
+// response status. Expired tokens get TokenExpired so clients know to
+// refresh, other rejections InvalidToken and anything else, e.g. an
+// unreachable JWKS, Internal.
+func TokenErrorStatus(err error) AppResponseStatus {
+	var verr *jwt.VerificationError
+	switch {
+	case errors.Is(err, jwt.ErrMissingToken):
+		return Unauthorized
+	case errors.Is(err, jwt.ErrTokenExpired):
+		return TokenExpired
+	case errors.As(err, &verr):
+		return InvalidToken
+	default:
+		return Internal
+	}
+}
+
+// TokenErrorResponse answers a request whose token was rejected, the
+// message names the reason.
+//
+//	app.Use(jwt.Middleware[*MyClaims](epicJwt, jwt.MiddlewareConfig{
+//		ErrorHandler: pkgep.TokenErrorResponse,
+//	}))
+func TokenErrorResponse(c *fiber.Ctx, err error) error {
+	status := TokenErrorStatus(err)
+	if status != InvalidToken {
+		return ErrorResponse(c, status)
+	}
+	return ErrorResponse(c, status, err.Error())
+}